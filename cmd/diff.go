@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Dmitrii Khramtsov
+// License: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/gen2brain/go-fitz"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/pflag"
+
+	config "github.com/dmikhr/pdfjuicer/configs"
+	"github.com/dmikhr/pdfjuicer/internal/compare"
+	dsp "github.com/dmikhr/pdfjuicer/internal/display"
+	"github.com/dmikhr/pdfjuicer/internal/extractor"
+	"github.com/dmikhr/pdfjuicer/internal/input"
+)
+
+// runDiff compares pages of two documents: pdfjuicer diff old.pdf new.pdf -o folder
+func runDiff(args []string) {
+	var cfg config.DiffConfig
+	var anyErr bool
+
+	flags := pflag.NewFlagSet("diff", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: pdfjuicer diff old.pdf new.pdf -o folder [options]")
+		flags.PrintDefaults()
+	}
+	flags.StringVarP(&cfg.SaveDir, "output", "o", "", "Specify output folder path")
+	flags.StringVarP(&cfg.ImgType, "format", "f", config.DefaultImgFormat,
+		"Highlighted diff images format: png/jpg/tiff/webp")
+	flags.Float64Var(&cfg.DPI, "dpi", config.DiffDPIDefault, "Resolution both documents are rendered at")
+	flags.IntVar(&cfg.Threshold, "threshold", config.DiffThresholdDefault,
+		"Color difference (0-255) which is still treated as the same pixel")
+	flags.StringVar(&cfg.Color, "color", config.DiffColorDefault, "Highlight color of changed pixels")
+	flags.BoolVar(&cfg.Align, "align", true, "Align pages to detect inserted and deleted pages, otherwise pages are matched by number")
+	flags.IntVar(&cfg.Distance, "align-distance", config.DiffDistanceDefault,
+		"Maximal perceptual hash distance in bits of pages aligned as the same page")
+	flags.IntVarP(&cfg.WorkersNum, "workers", "w", runtime.NumCPU(), "Set number of anynchronous workers")
+	flags.BoolVarP(&cfg.Quiet, "quiet", "q", false, "Quiet mode (no progress bar, no colored output)")
+
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	if flags.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "Specify two documents to compare")
+		anyErr = true
+	}
+	if cfg.SaveDir == "" {
+		fmt.Fprintln(os.Stderr, "Specify output folder (--output)")
+		anyErr = true
+	}
+	if err := input.ImgFormatValidator(cfg.ImgType); err != nil {
+		fmt.Fprintf(os.Stderr, "Unsupported image type: %s\n", cfg.ImgType)
+		anyErr = true
+	}
+	if cfg.DPI <= 0 {
+		fmt.Fprintln(os.Stderr, "Rendering DPI must be positive")
+		anyErr = true
+	}
+	if cfg.Threshold < 0 || cfg.Threshold > 255 {
+		fmt.Fprintln(os.Stderr, "Diff threshold must be in range 0-255")
+		anyErr = true
+	}
+	if cfg.Distance < 0 || cfg.Distance > 64 {
+		fmt.Fprintln(os.Stderr, "Alignment hash distance must be in range 0-64")
+		anyErr = true
+	}
+	if cfg.WorkersNum < 1 {
+		fmt.Fprintln(os.Stderr, "Number of workers must be positive")
+		anyErr = true
+	}
+	highlight, err := input.ColorExtractor(cfg.Color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid highlight color: %s\n", err)
+		anyErr = true
+	}
+	if anyErr {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	pathA, pathB := flags.Arg(0), flags.Arg(1)
+	docA, err := fitz.New(pathA)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer docA.Close()
+	docB, err := fitz.New(pathB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer docB.Close()
+
+	if err = os.MkdirAll(cfg.SaveDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Comparing %s (%s pages) with %s (%s pages)\n",
+		dsp.Fbg(pathA, cfg.Quiet), dsp.Fbg(strconv.Itoa(docA.NumPage()), cfg.Quiet),
+		dsp.Fbg(pathB, cfg.Quiet), dsp.Fbg(strconv.Itoa(docB.NumPage()), cfg.Quiet))
+
+	pairs := compare.Sequential(docA.NumPage(), docB.NumPage())
+	if cfg.Align {
+		fmt.Println("Aligning pages...")
+		hashesA := hashPages(docA, cfg)
+		hashesB := hashPages(docB, cfg)
+		pairs = compare.Align(hashesA, hashesB, cfg.Distance)
+	}
+
+	fmt.Println("Comparing pages...")
+	differ := &compare.Differ{
+		DocA:      docA,
+		DocB:      docB,
+		DPI:       cfg.DPI,
+		Threshold: cfg.Threshold,
+		Highlight: highlight,
+		ImgType:   cfg.ImgType,
+		SavePath:  cfg.SaveDir,
+	}
+	results, failed := comparePairs(differ, pairs, cfg.WorkersNum, cfg.Quiet)
+
+	summary := compare.NewSummary(pathA, pathB, results)
+	if err = summary.Save(filepath.Join(cfg.SaveDir, compare.SummaryName)); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Changed pages: %s, added: %s, removed: %s\n",
+		dsp.Fbg(strconv.Itoa(len(summary.Changed)), cfg.Quiet),
+		dsp.Fbg(strconv.Itoa(len(summary.Added)), cfg.Quiet),
+		dsp.Fbg(strconv.Itoa(len(summary.Removed)), cfg.Quiet))
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+	fmt.Println("Finished comparison")
+}
+
+// hashPages renders every page at low resolution and returns perceptual hashes in page order
+func hashPages(doc *fitz.Document, cfg config.DiffConfig) []uint64 {
+	hashes := &extractor.Hashes{}
+	measure := extractor.Page{
+		Doc:       doc,
+		ScaleDown: config.ImgScaleDownDefault,
+		DPI:       config.DiffAlignDPI,
+		Collector: hashes,
+	}
+	pagesToHash := make([]int, doc.NumPage())
+	for i := range pagesToHash {
+		pagesToHash[i] = i + 1
+	}
+	if failed := runJobs(measure, pagesToHash, cfg.WorkersNum, cfg.Quiet); failed > 0 {
+		log.Fatalf("Failed to hash %d page(s)", failed)
+	}
+
+	collected := hashes.Hashes()
+	ordered := make([]uint64, doc.NumPage())
+	for pageNum := range ordered {
+		ordered[pageNum] = collected[pageNum]
+	}
+	return ordered
+}
+
+// comparePairs compares page pairs with a pool of workers, results keep order of pairs
+func comparePairs(differ *compare.Differ, pairs []compare.Pair, workersNum int, quiet bool) ([]compare.Result, int) {
+	var wg sync.WaitGroup
+	results := make([]compare.Result, len(pairs))
+	errs := make([]error, len(pairs))
+	indexes := make(chan int, len(pairs))
+	for i := range pairs {
+		indexes <- i
+	}
+	close(indexes)
+
+	var bar *progressbar.ProgressBar
+	if !quiet {
+		bar = progressbar.Default(int64(len(pairs)))
+	}
+
+	for w := 0; w < workersNum; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = differ.Compare(pairs[i])
+				if !quiet {
+					if err := bar.Add(1); err != nil {
+						fmt.Fprintf(os.Stderr, "Progress bar encountered problem: %s\n", err)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Failed to compare page %d with page %d: %v", pairs[i].A+1, pairs[i].B+1, err)
+			failed++
+		}
+	}
+	return results, failed
+}
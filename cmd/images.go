@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Dmitrii Khramtsov
+// License: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/pflag"
+
+	config "github.com/dmikhr/pdfjuicer/configs"
+	dsp "github.com/dmikhr/pdfjuicer/internal/display"
+	"github.com/dmikhr/pdfjuicer/internal/embedded"
+	"github.com/dmikhr/pdfjuicer/internal/input"
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
+)
+
+// runImages saves images embedded into pages: pdfjuicer images -s doc.pdf -o folder
+func runImages(args []string) {
+	var cfg config.ImagesConfig
+	var anyErr bool
+
+	flags := pflag.NewFlagSet("images", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: pdfjuicer images -s doc.pdf -o folder [options]")
+		flags.PrintDefaults()
+	}
+	flags.StringVarP(&cfg.SourcePath, "source", "s", "", "Specify path to source file (pdf)")
+	flags.StringVarP(&cfg.SaveDir, "output", "o", "", "Specify output folder path")
+	flags.StringVarP(&cfg.Pages, "pages", "P", "", "Extract images of specific pages, example: 1,3,4-10")
+	flags.StringVarP(&cfg.Prefix, "prefix", "p", config.DefaultFilenamePrefix, "Prefix for a filename")
+	flags.StringVarP(&cfg.Postfix, "postfix", "x", "", "Postfix for a filename")
+	flags.StringVar(&cfg.Name, "name", config.ImagesNameDefault,
+		"Image file name template without extension, placeholders: {page} and {index} (required), {prefix}, {postfix}")
+	flags.BoolVarP(&cfg.Quiet, "quiet", "q", false, "Quiet mode (no progress bar, no colored output)")
+
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.SourcePath == "" || cfg.SaveDir == "" {
+		fmt.Fprintln(os.Stderr, "Specify source file (--source) and output folder (--output)")
+		anyErr = true
+	}
+	if err := input.FilenameValidator(cfg.Prefix); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid prefix: %s. Error: %s\n", cfg.Prefix, err)
+		anyErr = true
+	}
+	if err := input.FilenameValidator(cfg.Postfix); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid postfix: %s. Error: %s\n", cfg.Postfix, err)
+		anyErr = true
+	}
+	if err := input.ImageNameTemplateValidator(cfg.Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid image name template: %s. Error: %s\n", cfg.Name, err)
+		anyErr = true
+	}
+	if anyErr {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	// images are read from PDF objects, the renderer only hands over decoded pixels
+	doc, err := pdf.Open(cfg.SourcePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var pagesToExtract []int
+	if cfg.Pages != "" {
+		pagesToExtract, err = input.PagesExtractor(cfg.Pages, doc.NumPage())
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		for i := 1; i <= doc.NumPage(); i++ {
+			pagesToExtract = append(pagesToExtract, i)
+		}
+	}
+
+	if err = os.MkdirAll(cfg.SaveDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Extracting embedded images of %s, save folder: %s\n",
+		dsp.Fbg(cfg.SourcePath, cfg.Quiet), dsp.Fbg(cfg.SaveDir, cfg.Quiet))
+
+	var bar *progressbar.ProgressBar
+	if !cfg.Quiet {
+		bar = progressbar.Default(int64(len(pagesToExtract)))
+	}
+
+	// pages are walked in order, so the first placement of an image names its file
+	catalog := &embedded.Catalog{Source: cfg.SourcePath}
+	skipped := make(map[int]bool)
+	for _, pageNum := range pagesToExtract {
+		images, err := embedded.PageImages(doc, pageNum-1)
+		if err != nil {
+			log.Fatalf("Failed to read images of page %d: %s", pageNum, err)
+		}
+		index := 0
+		for _, img := range images {
+			if catalog.Place(pageNum-1, img) || skipped[img.Object] {
+				continue
+			}
+			index++
+			name := imageName(cfg, pageNum, index)
+			file, mask, err := saveImage(cfg.SaveDir, name, img)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipped image %d of page %d: %s\n", index, pageNum, err)
+				if img.Object != 0 {
+					skipped[img.Object] = true
+				}
+				continue
+			}
+			catalog.Add(pageNum-1, img, file, mask)
+		}
+		if !cfg.Quiet {
+			if err = bar.Add(1); err != nil {
+				fmt.Fprintf(os.Stderr, "Progress bar encountered problem: %s\n", err)
+			}
+		}
+	}
+
+	if err = catalog.Save(filepath.Join(cfg.SaveDir, embedded.CatalogName)); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Saved %s image(s)\n", dsp.Fbg(strconv.Itoa(len(catalog.Images)), cfg.Quiet))
+}
+
+// saveImage writes image and its mask named after the image, names of written files are returned
+func saveImage(dir, name string, img embedded.Image) (string, string, error) {
+	data, err := img.Data()
+	if err != nil {
+		return "", "", err
+	}
+	file := name + "." + img.Format
+	if err = os.WriteFile(filepath.Join(dir, file), data, 0644); err != nil {
+		log.Fatal(err)
+	}
+	if !img.HasMask() {
+		return file, "", nil
+	}
+	data, err = img.Mask()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Skipped mask of %s: %s\n", file, err)
+		return file, "", nil
+	}
+	mask := name + ".mask.png"
+	if err = os.WriteFile(filepath.Join(dir, mask), data, 0644); err != nil {
+		log.Fatal(err)
+	}
+	return file, mask, nil
+}
+
+// imageName fills image file name template, page is one-based
+func imageName(cfg config.ImagesConfig, pageNum, index int) string {
+	return strings.NewReplacer(
+		"{page}", fmt.Sprintf("%03d", pageNum),
+		"{index}", fmt.Sprintf("%02d", index),
+		"{prefix}", cfg.Prefix,
+		"{postfix}", cfg.Postfix,
+	).Replace(cfg.Name)
+}
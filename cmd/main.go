@@ -4,30 +4,66 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"image"
+	"image/color"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gen2brain/go-fitz"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/pflag"
 
 	config "github.com/dmikhr/pdfjuicer/configs"
+	"github.com/dmikhr/pdfjuicer/internal/animation"
+	"github.com/dmikhr/pdfjuicer/internal/command"
 	dsp "github.com/dmikhr/pdfjuicer/internal/display"
 	"github.com/dmikhr/pdfjuicer/internal/extractor"
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
 	"github.com/dmikhr/pdfjuicer/internal/input"
+	"github.com/dmikhr/pdfjuicer/internal/manifest"
+	"github.com/dmikhr/pdfjuicer/internal/ocr"
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
+	"github.com/dmikhr/pdfjuicer/internal/sheet"
+	"github.com/dmikhr/pdfjuicer/internal/tiles"
 )
 
 func main() {
 	var sizeX, sizeY, thumbSizeX, thumbSizeY int
+	var cellX, cellY int
+	var sheetBg color.RGBA
+	var cropRegion imageutils.Region
+	var background extractor.Background
+	var watermarkColor color.RGBA
+	var captionColor, captionBg color.RGBA
+	var canvas extractor.Canvas
+	var canvasX, canvasY, thumbCanvasX, thumbCanvasY int
+	var renditions []input.Rendition
+	var spriteMaxX, spriteMaxY int
+	var stitchColor color.RGBA
 	var err error
 	var anyErr bool
 
 	var cfg config.Config
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "images":
+			runImages(os.Args[2:])
+			return
+		}
+	}
+
 	workersNumDefault := runtime.NumCPU()
 
 	pflag.StringVarP(&cfg.SourcePath, "source", "s", "",
@@ -42,7 +78,20 @@ func main() {
 	pflag.Float64VarP(&cfg.Image.ImgScaleDown, "scale", "C", config.ImgScaleDownDefault,
 		"Specify image scaling down factor, example 5, for example 5 means output image will be 5 times smaller than original image")
 	pflag.StringVarP(&cfg.Image.ImgType, "format", "F", config.DefaultImgFormat,
-		"Specify output image format (png/jpg)")
+		"Specify output image format (png/jpg/tiff/webp)")
+	pflag.StringVar(&cfg.Image.ColorMode, "color", config.DefaultColorMode,
+		"Output color mode: rgb, gray (8-bit grayscale) or bw (1-bit black and white)")
+	pflag.StringVar(&cfg.Image.BWMethod, "bw-method", config.DefaultBilevelMethod,
+		"Black and white conversion method: threshold, otsu or dither (Floyd-Steinberg)")
+	pflag.IntVar(&cfg.Image.BWThreshold, "bw-threshold", config.BilevelThresholdDefault,
+		"Brightness (0-255) above which pixels become white with --bw-method=threshold")
+
+	pflag.StringVar(&cfg.Image.Background, "background", "",
+		"Replace white page background: transparent or color like #RRGGBB, formats without alpha (jpg) get white background")
+
+	pflag.BoolVar(&cfg.Image.Invert, "invert", false, "Invert page colors")
+	pflag.BoolVar(&cfg.Image.DarkMode, "dark-mode", false,
+		"Invert page lightness keeping hue, so charts and colored text stay readable on dark background")
 
 	pflag.StringVarP(&cfg.Pages, "pages", "P", "",
 		"Use this flag to extract specific pages, example: 2,3,6-8,10")
@@ -52,6 +101,158 @@ func main() {
 		"Specify thumbnails scaling down factor, for example 5 means thumbnail will be 5 times smaller than original image")
 	pflag.StringVarP(&cfg.Thumb.ThumbnailsSize, "tsize", "z", "",
 		"Specify thumbnails size e.g. 64x64")
+	pflag.StringVar(&cfg.Thumb.Format, "tformat", "", "Thumbnails image format (png/jpg/tiff/webp), same as --format if not specified")
+	pflag.IntVar(&cfg.Thumb.Quality, "tquality", config.ImgQualityDefault, "Thumbnails quality (1-100) for jpg format")
+	pflag.StringVar(&cfg.Thumb.Dir, "tdir", config.ThumbnailsDir, "Thumbnails folder inside output folder")
+	pflag.StringVar(&cfg.Thumb.Name, "tname", config.ThumbNameDefault,
+		"Thumbnails file name template without extension, placeholders: {page}, {prefix}, {postfix}")
+
+	pflag.BoolVar(&cfg.Sprite.Enabled, "sprite", false, "Pack thumbnails into sprite sheets with a map of page rectangles")
+	pflag.IntVar(&cfg.Sprite.Columns, "sprite-cols", config.SpriteColumnsDefault, "Maximal number of thumbnails in a sprite row")
+	pflag.IntVar(&cfg.Sprite.Rows, "sprite-rows", 0, "Maximal number of rows on a sprite sheet, 0 means as many as fit")
+	pflag.StringVar(&cfg.Sprite.MaxSize, "sprite-max", config.SpriteMaxSizeDefault, "Maximal sprite sheet size")
+	pflag.StringVar(&cfg.Sprite.Map, "sprite-map", config.SpriteMapDefault, "Sprite map format: json, css or vtt")
+	pflag.Float64Var(&cfg.Sprite.Interval, "sprite-interval", config.SpriteIntervalDefault,
+		"Seconds each page is shown in vtt sprite map")
+
+	pflag.BoolVar(&cfg.Sheet.Enabled, "contact-sheet", false,
+		"Lay extracted pages out in a grid on contact sheet images instead of saving each page")
+	pflag.IntVar(&cfg.Sheet.Columns, "sheet-cols", config.SheetColumnsDefault, "Number of columns on a contact sheet")
+	pflag.IntVar(&cfg.Sheet.Rows, "sheet-rows", config.SheetRowsDefault,
+		"Maximum number of rows on a contact sheet, remaining pages go to the next sheet")
+	pflag.StringVar(&cfg.Sheet.CellSize, "sheet-cell", config.SheetCellDefault,
+		"Size of a contact sheet cell, pages are fitted into it keeping aspect ratio")
+	pflag.IntVar(&cfg.Sheet.Spacing, "sheet-spacing", config.SheetSpacingDefault,
+		"Spacing between contact sheet cells in pixels")
+	pflag.StringVar(&cfg.Sheet.Background, "sheet-bg", config.SheetBackgroundDefault,
+		"Contact sheet background color, example #ffffff")
+	pflag.BoolVar(&cfg.Sheet.Captions, "sheet-captions", false, "Put page numbers under contact sheet cells")
+
+	pflag.StringVar(&cfg.Tiles.Format, "tiles", "",
+		"Cut pages into multi-resolution tile pyramids for zoomable viewers (dzi/iiif/xyz)")
+	pflag.IntVar(&cfg.Tiles.Size, "tile-size", config.TileSizeDefault, "Tile size in pixels")
+	pflag.IntVar(&cfg.Tiles.Overlap, "tile-overlap", config.TileOverlapDefault,
+		"Overlap between neighbouring tiles in pixels (dzi only)")
+	pflag.Float64Var(&cfg.Tiles.DPI, "tile-dpi", config.TileDPIDefault, "Resolution pages are rendered at for tiling")
+	pflag.StringVar(&cfg.Tiles.BaseURL, "iiif-base", "",
+		"Base URL pages are served from, used for identifiers in IIIF info.json")
+
+	pflag.StringVar(&cfg.Watermark.Text, "watermark-text", "", "Stamp text like copyright or DRAFT onto pages")
+	pflag.StringVar(&cfg.Watermark.FontPath, "watermark-font", "",
+		"Path to TrueType/OpenType font for watermark text, embedded Go font is used by default")
+	pflag.Float64Var(&cfg.Watermark.TextSize, "watermark-size", config.WatermarkTextSizeDefault,
+		"Watermark font size in percent of image width")
+	pflag.StringVar(&cfg.Watermark.TextColor, "watermark-color", config.WatermarkTextColorDefault, "Watermark text color")
+	pflag.Float64Var(&cfg.Watermark.TextOpacity, "watermark-opacity", config.WatermarkTextOpacityDefault,
+		"Watermark text opacity from 0 to 1")
+	pflag.Float64Var(&cfg.Watermark.TextAngle, "watermark-angle", 0,
+		"Watermark text angle in degrees counterclockwise, example 45 for diagonal text")
+	pflag.StringVar(&cfg.Watermark.TextPosition, "watermark-position", config.WatermarkTextPositionDefault,
+		"Watermark text position: center, top, bottom, left, right, top-left, top-right, bottom-left, bottom-right "+
+			"or tile to repeat it over the page")
+	pflag.StringVar(&cfg.Watermark.ImagePath, "watermark-image", "", "Path to png/jpg image like logo stamped onto pages")
+	pflag.Float64Var(&cfg.Watermark.ImageScale, "watermark-image-scale", config.WatermarkImageScaleDefault,
+		"Watermark image width in percent of page image width")
+	pflag.Float64Var(&cfg.Watermark.ImageOpacity, "watermark-image-opacity", config.WatermarkImageOpacityDefault,
+		"Watermark image opacity from 0 to 1")
+	pflag.StringVar(&cfg.Watermark.ImagePosition, "watermark-image-position", config.WatermarkImagePositionDefault,
+		"Watermark image position, same values as --watermark-position")
+	pflag.BoolVar(&cfg.Watermark.Thumbnails, "watermark-thumbs", false, "Stamp watermarks onto thumbnails as well")
+
+	pflag.StringVar(&cfg.Caption.Template, "caption", "",
+		"Add caption band to pages, template supports {doc}, {page} and {total}, example \"{doc} — p.{page}/{total}\"")
+	pflag.StringVar(&cfg.Caption.Position, "caption-position", config.CaptionPositionDefault,
+		"Caption band position: top or bottom")
+	pflag.StringVar(&cfg.Caption.Align, "caption-align", config.CaptionAlignDefault,
+		"Caption text alignment: left, center or right")
+	pflag.Float64Var(&cfg.Caption.Size, "caption-size", config.CaptionSizeDefault,
+		"Caption font size in percent of image width")
+	pflag.StringVar(&cfg.Caption.Color, "caption-color", config.CaptionColorDefault, "Caption text color")
+	pflag.StringVar(&cfg.Caption.Background, "caption-bg", config.CaptionBackgroundDefault, "Caption band color")
+
+	pflag.StringVar(&cfg.Canvas.Size, "canvas", "",
+		"Place page keeping aspect ratio on a canvas of fixed size, example 1080x1080")
+	pflag.StringVar(&cfg.Canvas.ThumbSize, "tcanvas", "", "Place thumbnails on a canvas of fixed size, example 128x128")
+	pflag.StringVar(&cfg.Canvas.PadColor, "pad-color", config.CanvasPadColorDefault,
+		"Canvas color around the page: #RRGGBB or transparent")
+	pflag.StringVar(&cfg.Canvas.Align, "align", config.CanvasAlignDefault,
+		"Page position on canvas: center, top, bottom, left, right, top-left, top-right, bottom-left, bottom-right")
+	pflag.IntVar(&cfg.Canvas.Margin, "canvas-margin", 0, "Minimal distance in pixels between page and canvas edges")
+	pflag.IntVar(&cfg.Canvas.Border, "border", 0, "Width in pixels of a border drawn around the page on canvas")
+	pflag.StringVar(&cfg.Canvas.BorderColor, "border-color", config.CanvasBorderColorDefault, "Border color")
+	pflag.BoolVar(&cfg.Canvas.Shadow, "shadow", false, "Drop shadow under the page on canvas")
+
+	pflag.StringVar(&cfg.Animate.Path, "animate", "",
+		"Combine pages into animation saved into output folder, format by extension: gif, png (APNG) or webp")
+	pflag.IntVar(&cfg.Animate.Delay, "anim-delay", config.AnimDelayDefault, "Animation frame delay in milliseconds")
+	pflag.IntVar(&cfg.Animate.Loop, "anim-loop", 0, "Number of times animation is played, 0 means infinite")
+	pflag.IntVar(&cfg.Animate.Colors, "anim-colors", config.AnimColorsDefault, "Number of colors (2-256) in gif frame palette")
+	pflag.BoolVar(&cfg.Animate.Dither, "anim-dither", false, "Dither gif frames reduced to palette")
+
+	pflag.StringVar(&cfg.Stitch.Mode, "stitch", "", "Concatenate pages into one long image: vertical or horizontal")
+	pflag.IntVar(&cfg.Stitch.Gap, "stitch-gap", 0, "Gap in pixels between stitched pages")
+	pflag.StringVar(&cfg.Stitch.Color, "stitch-color", config.StitchColorDefault, "Color of gaps between stitched pages")
+	pflag.IntVar(&cfg.Stitch.MaxLength, "stitch-max", 0,
+		"Maximal stitched image length in pixels, longer images are split into parts, 0 means format limit")
+
+	pflag.StringVar(&cfg.Renditions.List, "renditions", "",
+		"Additionally save every page in several widths with optional format, example 320w,640w,1280w:webp,2560w:jpg")
+	pflag.StringVar(&cfg.Renditions.Srcset, "srcset", "", "Write per page srcset snippet listing renditions: html or json")
+
+	pflag.StringVar(&cfg.Crop.Region, "crop", "",
+		"Crop region x,y,w,h from every page before resizing, values in points (default), px or %, example 10%,10%,80%,50%")
+	pflag.StringVar(&cfg.Crop.SpecPath, "crop-spec", "",
+		"Path to file with per page crop regions, one \"pages x,y,w,h\" pair per line")
+
+	pflag.StringVar(&cfg.Rotate.Turn, "rotate", "",
+		"Rotate pages clockwise: 90, 180, 270 or auto to make text upright")
+	pflag.BoolVar(&cfg.Rotate.Deskew, "deskew", false, "Correct small skew of scanned pages")
+
+	pflag.StringVar(&cfg.Split.Mode, "split-spreads", "",
+		"Cut two-page spreads into halves: vertical, horizontal or auto (landscape pages only)")
+	pflag.BoolVar(&cfg.Split.Gutter, "split-gutter", false, "Cut spreads at detected gutter instead of exact center")
+	pflag.BoolVar(&cfg.Split.RTL, "rtl", false, "Right-to-left reading order, right half of a spread goes first")
+
+	pflag.Float64Var(&cfg.Blank.Threshold, "skip-blank", config.BlankThresholdDefault,
+		"Skip blank pages, optional value is maximal percent of ink pixels on a blank page")
+	pflag.Lookup("skip-blank").NoOptDefVal = strconv.FormatFloat(config.BlankThresholdDefault, 'f', -1, 64)
+	pflag.BoolVar(&cfg.Blank.TextLayer, "blank-text", false, "Treat page as blank only if its text layer is empty too")
+	pflag.BoolVar(&cfg.Blank.Move, "blank-move", false,
+		"Save blank pages into "+config.BlankDir+" folder instead of skipping them")
+
+	pflag.IntVar(&cfg.Dedupe.Distance, "dedupe", config.DedupeDistanceDefault,
+		"Drop runs of nearly identical pages keeping the last one, optional value is maximal hash distance in bits")
+	pflag.Lookup("dedupe").NoOptDefVal = strconv.Itoa(config.DedupeDistanceDefault)
+	pflag.StringVar(&cfg.Dedupe.Hash, "dedupe-hash", config.DedupeHashDefault, "Perceptual hash of pages: ahash, dhash or phash")
+	pflag.BoolVar(&cfg.Dedupe.Group, "dedupe-group", false, "Keep duplicate pages and only group them in manifest")
+
+	pflag.BoolVar(&cfg.Trim.Enabled, "trim", false, "Trim page margins to content before resizing")
+	pflag.BoolVar(&cfg.Trim.Uniform, "trim-uniform", false,
+		"Trim all pages with the same box covering content of every page")
+	pflag.IntVar(&cfg.Trim.Tolerance, "trim-tolerance", config.TrimToleranceDefault,
+		"Color difference (0-255) from background which is still treated as margin")
+	pflag.IntVar(&cfg.Trim.Padding, "trim-padding", config.TrimPaddingDefault, "Padding kept around trimmed content in pixels")
+
+	pflag.BoolVar(&cfg.Links, "links", false,
+		"Save hyperlinks of every page scaled to the page image as JSON and HTML image map")
+
+	pflag.StringVar(&cfg.Words, "words", "",
+		"Save text layer of every page as words with boxes in pixels of the page image: json, hocr or alto")
+
+	pflag.StringVar(&cfg.OCR.Command, "ocr", config.OCRCommandDefault,
+		"Recognize text of pages without usable text layer, optional value is OCR command with {image} and {output} placeholders")
+	pflag.Lookup("ocr").NoOptDefVal = config.OCRCommandDefault
+	pflag.IntVar(&cfg.OCR.Timeout, "ocr-timeout", config.OCRTimeoutDefault, "Time limit of OCR command per page in seconds")
+	pflag.BoolVar(&cfg.OCR.Force, "ocr-force", false, "Recognize all pages including ones with text layer")
+
+	pflag.StringVar(&cfg.Exec.Page, "exec-page", "",
+		"Run command on every file written for a page, placeholders: {path} and {page}")
+	pflag.StringVar(&cfg.Exec.Done, "exec-done", "",
+		"Run command after all pages are extracted, placeholders: {manifest} and {dir}")
+	pflag.IntVar(&cfg.Exec.Jobs, "exec-jobs", workersNumDefault, "Maximal number of page commands running at once")
+	pflag.IntVar(&cfg.Exec.Timeout, "exec-timeout", config.ExecTimeoutDefault, "Time limit of every hook command in seconds")
+
+	pflag.BoolVar(&cfg.Manifest, "manifest", false, "Write manifest.json with per page results into output folder")
 
 	pflag.BoolVarP(&cfg.VersionFlag, "version", "v", false, "Show version")
 
@@ -61,17 +262,28 @@ func main() {
 	pflag.BoolVarP(&cfg.Quiet, "quiet", "q", false, "Quiet mode (no progress bar, no colored output)")
 
 	pflag.Parse()
+	cfg.Blank.Enabled = pflag.CommandLine.Changed("skip-blank")
+	cfg.Dedupe.Enabled = pflag.CommandLine.Changed("dedupe")
+	if !pflag.CommandLine.Changed("ocr") {
+		cfg.OCR.Command = ""
+	}
 
 	// show help if called with no params
 	if pflag.NFlag() == 0 && pflag.NArg() == 0 {
 		fmt.Println(config.About())
 		pflag.Usage()
+		fmt.Println("\nCompare two documents: pdfjuicer diff old.pdf new.pdf -o folder, see pdfjuicer diff --help")
+		fmt.Println("Extract embedded images: pdfjuicer images -s doc.pdf -o folder, see pdfjuicer images --help")
 		os.Exit(0)
 	}
 
 	// if called with unsupported arguments
-	if pflag.NFlag() == 0 && pflag.NArg() > 0 {
+	if pflag.NArg() > 0 {
 		fmt.Printf("Unsupported arguments: %s\n", pflag.Args())
+		// optional values given without "=" (e.g. --ocr "cmd") are parsed as arguments
+		if pflag.NFlag() > 0 {
+			fmt.Println("Values of --ocr, --skip-blank and --dedupe must be joined with \"=\", e.g. --dedupe=3")
+		}
 		os.Exit(1)
 	}
 
@@ -88,10 +300,58 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Unsupported image type: %s\n", cfg.Image.ImgType)
 		anyErr = true
 	}
+	if err = input.ColorModeValidator(cfg.Image.ColorMode); err != nil {
+		fmt.Fprintf(os.Stderr, "Unsupported color mode: %s\n", cfg.Image.ColorMode)
+		anyErr = true
+	}
+	if cfg.Image.ColorMode != config.DefaultColorMode && (cfg.Animate.Path != "" || cfg.Tiles.Format != "") {
+		fmt.Fprintln(os.Stderr, "Color mode (--color) can't be combined with animation (--animate) or tiles (--tiles)")
+		anyErr = true
+	}
+	if err = input.BilevelMethodValidator(cfg.Image.BWMethod); err != nil {
+		fmt.Fprintf(os.Stderr, "Unsupported black and white conversion method: %s\n", cfg.Image.BWMethod)
+		anyErr = true
+	}
+	if cfg.Image.BWThreshold < 0 || cfg.Image.BWThreshold > 255 {
+		fmt.Fprintln(os.Stderr, "Black and white threshold must be in range 0-255")
+		anyErr = true
+	}
+	if cfg.Image.Background == config.TransparentBackground {
+		background.Transparent = true
+	} else if cfg.Image.Background != "" {
+		bgColor, err := input.ColorExtractor(cfg.Image.Background)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid background (transparent or #RRGGBB): %s\n", err)
+			anyErr = true
+		}
+		background.Color = &bgColor
+	}
+	if cfg.Image.Invert && cfg.Image.DarkMode {
+		fmt.Fprintln(os.Stderr, "Choose either color inversion (--invert) or dark mode (--dark-mode)")
+		anyErr = true
+	}
 	if cfg.Thumb.ThumbnailsSize != "" && cfg.Thumb.ThumbScaleDown != config.ThumbScaleDownDefault {
 		fmt.Fprintln(os.Stderr, "Choose either scaling factor (--scale) or exact image size for resizing (--size)")
 		anyErr = true
 	}
+	if cfg.Thumb.Format != "" {
+		if err = input.ImgFormatValidator(cfg.Thumb.Format); err != nil {
+			fmt.Fprintf(os.Stderr, "Unsupported thumbnails image type: %s\n", cfg.Thumb.Format)
+			anyErr = true
+		}
+	}
+	if cfg.Thumb.Quality < 1 || cfg.Thumb.Quality > 100 {
+		fmt.Fprintln(os.Stderr, "Thumbnails quality must be in range 1-100")
+		anyErr = true
+	}
+	if !filepath.IsLocal(cfg.Thumb.Dir) {
+		fmt.Fprintf(os.Stderr, "Thumbnails folder must be inside output folder: %s\n", cfg.Thumb.Dir)
+		anyErr = true
+	}
+	if err = input.NameTemplateValidator(cfg.Thumb.Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid thumbnails name template: %s. Error: %s\n", cfg.Thumb.Name, err)
+		anyErr = true
+	}
 
 	if cfg.SourcePath == "" {
 		fmt.Fprintln(os.Stderr, "No source pdf file was specified")
@@ -120,6 +380,343 @@ func main() {
 		anyErr = true
 	}
 
+	if cfg.Sheet.Enabled {
+		if cfg.Thumb.CreateThumbnails {
+			fmt.Fprintln(os.Stderr, "Contact sheet mode can't be combined with thumbnails generation (--thumb)")
+			anyErr = true
+		}
+		if cfg.Sheet.Columns <= 0 || cfg.Sheet.Rows <= 0 {
+			fmt.Fprintln(os.Stderr, "Contact sheet must have at least 1 column and 1 row")
+			anyErr = true
+		}
+		if cfg.Sheet.Spacing < 0 {
+			fmt.Fprintln(os.Stderr, "Contact sheet spacing can't be negative")
+			anyErr = true
+		}
+		if cellX, cellY, err = input.ImgSizeExtractor(cfg.Sheet.CellSize); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid contact sheet cell size (example: 240x320): %s\n", err)
+			anyErr = true
+		}
+		if sheetBg, err = input.ColorExtractor(cfg.Sheet.Background); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid contact sheet background: %s\n", err)
+			anyErr = true
+		}
+	}
+
+	if cfg.Sprite.Enabled {
+		if !cfg.Thumb.CreateThumbnails {
+			fmt.Fprintln(os.Stderr, "Sprite sheets (--sprite) require thumbnails generation (--thumb)")
+			anyErr = true
+		}
+		if cfg.Sprite.Columns <= 0 || cfg.Sprite.Rows < 0 {
+			fmt.Fprintln(os.Stderr, "Sprite must have at least 1 column, rows can't be negative")
+			anyErr = true
+		}
+		if spriteMaxX, spriteMaxY, err = input.ImgSizeExtractor(cfg.Sprite.MaxSize); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid sprite sheet size (example: 4096x4096): %s\n", err)
+			anyErr = true
+		}
+		if cfg.Sprite.Map != "json" && cfg.Sprite.Map != "css" && cfg.Sprite.Map != "vtt" {
+			fmt.Fprintf(os.Stderr, "Unsupported sprite map format: %s\n", cfg.Sprite.Map)
+			anyErr = true
+		}
+		if cfg.Sprite.Interval <= 0 {
+			fmt.Fprintln(os.Stderr, "Sprite interval must be positive")
+			anyErr = true
+		}
+	}
+
+	if cfg.Tiles.Format != "" {
+		if err = input.TilesFormatValidator(cfg.Tiles.Format); err != nil {
+			fmt.Fprintf(os.Stderr, "Unsupported tiles format: %s\n", cfg.Tiles.Format)
+			anyErr = true
+		}
+		if cfg.Sheet.Enabled || cfg.Thumb.CreateThumbnails {
+			fmt.Fprintln(os.Stderr, "Tiles can't be combined with contact sheet (--contact-sheet) or thumbnails (--thumb)")
+			anyErr = true
+		}
+		if cfg.Tiles.Size <= 0 || cfg.Tiles.Overlap < 0 || cfg.Tiles.DPI <= 0 {
+			fmt.Fprintln(os.Stderr, "Tile size and DPI must be positive, overlap can't be negative")
+			anyErr = true
+		}
+	}
+
+	if cfg.Watermark.Text != "" || cfg.Watermark.ImagePath != "" {
+		if watermarkColor, err = input.ColorExtractor(cfg.Watermark.TextColor); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid watermark color: %s\n", err)
+			anyErr = true
+		}
+		for _, position := range []string{cfg.Watermark.TextPosition, cfg.Watermark.ImagePosition} {
+			if err = input.WatermarkPositionValidator(position); err != nil {
+				fmt.Fprintf(os.Stderr, "Unsupported watermark position: %s\n", position)
+				anyErr = true
+			}
+		}
+		if cfg.Watermark.TextSize <= 0 || cfg.Watermark.ImageScale <= 0 {
+			fmt.Fprintln(os.Stderr, "Watermark size and image scale must be positive")
+			anyErr = true
+		}
+		if cfg.Watermark.TextOpacity < 0 || cfg.Watermark.TextOpacity > 1 ||
+			cfg.Watermark.ImageOpacity < 0 || cfg.Watermark.ImageOpacity > 1 {
+			fmt.Fprintln(os.Stderr, "Watermark opacity must be in range 0-1")
+			anyErr = true
+		}
+	}
+
+	if cfg.Caption.Template != "" {
+		if err = input.CaptionTemplateValidator(cfg.Caption.Template); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid caption template, supported placeholders are {doc}, {page}, {total}: %s\n", err)
+			anyErr = true
+		}
+		if cfg.Caption.Position != "top" && cfg.Caption.Position != "bottom" {
+			fmt.Fprintf(os.Stderr, "Unsupported caption position: %s\n", cfg.Caption.Position)
+			anyErr = true
+		}
+		if cfg.Caption.Align != "left" && cfg.Caption.Align != "center" && cfg.Caption.Align != "right" {
+			fmt.Fprintf(os.Stderr, "Unsupported caption alignment: %s\n", cfg.Caption.Align)
+			anyErr = true
+		}
+		if cfg.Caption.Size <= 0 {
+			fmt.Fprintln(os.Stderr, "Caption size must be positive")
+			anyErr = true
+		}
+		if captionColor, err = input.ColorExtractor(cfg.Caption.Color); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid caption color: %s\n", err)
+			anyErr = true
+		}
+		if captionBg, err = input.ColorExtractor(cfg.Caption.Background); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid caption band color: %s\n", err)
+			anyErr = true
+		}
+	}
+
+	if cfg.Canvas.Size != "" || cfg.Canvas.ThumbSize != "" {
+		if cfg.Canvas.Size != "" && (cfg.Image.ImgSize != "" || cfg.Image.ImgScaleDown != config.ImgScaleDownDefault) {
+			fmt.Fprintln(os.Stderr, "Canvas (--canvas) can't be combined with image size (--size) or scaling factor (--scale)")
+			anyErr = true
+		}
+		if cfg.Canvas.ThumbSize != "" && cfg.Thumb.ThumbnailsSize != "" {
+			fmt.Fprintln(os.Stderr, "Thumbnails canvas (--tcanvas) can't be combined with thumbnails size (--tsize)")
+			anyErr = true
+		}
+		if cfg.Canvas.Size != "" {
+			if canvasX, canvasY, err = input.ImgSizeExtractor(cfg.Canvas.Size); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid canvas size (example: 1080x1080): %s\n", err)
+				anyErr = true
+			}
+		}
+		if cfg.Canvas.ThumbSize != "" {
+			if thumbCanvasX, thumbCanvasY, err = input.ImgSizeExtractor(cfg.Canvas.ThumbSize); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid thumbnails canvas size (example: 128x128): %s\n", err)
+				anyErr = true
+			}
+		}
+		if cfg.Canvas.PadColor != config.TransparentBackground {
+			if canvas.Color, err = input.ColorExtractor(cfg.Canvas.PadColor); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid pad color (transparent or #RRGGBB): %s\n", err)
+				anyErr = true
+			}
+		}
+		if canvas.BorderColor, err = input.ColorExtractor(cfg.Canvas.BorderColor); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid border color: %s\n", err)
+			anyErr = true
+		}
+		if err = input.PositionValidator(cfg.Canvas.Align); err != nil {
+			fmt.Fprintf(os.Stderr, "Unsupported canvas alignment: %s\n", cfg.Canvas.Align)
+			anyErr = true
+		}
+		if cfg.Canvas.Margin < 0 || cfg.Canvas.Border < 0 {
+			fmt.Fprintln(os.Stderr, "Canvas margin and border can't be negative")
+			anyErr = true
+		}
+		canvas.Align = imageutils.Anchor(cfg.Canvas.Align)
+		canvas.Margin = cfg.Canvas.Margin
+		canvas.Border = cfg.Canvas.Border
+		canvas.Shadow = cfg.Canvas.Shadow
+	}
+
+	if cfg.Animate.Path != "" {
+		ext := strings.ToLower(filepath.Ext(cfg.Animate.Path))
+		if ext != ".gif" && ext != ".png" && ext != ".apng" && ext != ".webp" {
+			fmt.Fprintf(os.Stderr, "Unsupported animation format, use .gif, .png, .apng or .webp: %s\n", cfg.Animate.Path)
+			anyErr = true
+		}
+		if err = input.FilenameValidator(cfg.Animate.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid animation file name: %s. Error: %s\n", cfg.Animate.Path, err)
+			anyErr = true
+		}
+		if cfg.Sheet.Enabled || cfg.Tiles.Format != "" || cfg.Thumb.CreateThumbnails || cfg.Renditions.List != "" {
+			fmt.Fprintln(os.Stderr, "Animation can't be combined with contact sheet (--contact-sheet), tiles (--tiles), "+
+				"thumbnails (--thumb) or renditions (--renditions)")
+			anyErr = true
+		}
+		if cfg.Animate.Delay <= 0 || cfg.Animate.Loop < 0 {
+			fmt.Fprintln(os.Stderr, "Animation delay must be positive, loop count can't be negative")
+			anyErr = true
+		}
+		if cfg.Animate.Colors < 2 || cfg.Animate.Colors > 256 {
+			fmt.Fprintln(os.Stderr, "Animation palette must have 2-256 colors")
+			anyErr = true
+		}
+	}
+
+	if cfg.Stitch.Mode != "" {
+		if cfg.Stitch.Mode != "vertical" && cfg.Stitch.Mode != "horizontal" {
+			fmt.Fprintf(os.Stderr, "Unsupported stitch direction: %s\n", cfg.Stitch.Mode)
+			anyErr = true
+		}
+		if cfg.Sheet.Enabled || cfg.Tiles.Format != "" || cfg.Animate.Path != "" ||
+			cfg.Thumb.CreateThumbnails || cfg.Renditions.List != "" {
+			fmt.Fprintln(os.Stderr, "Stitching can't be combined with contact sheet (--contact-sheet), tiles (--tiles), "+
+				"animation (--animate), thumbnails (--thumb) or renditions (--renditions)")
+			anyErr = true
+		}
+		if cfg.Stitch.Gap < 0 || cfg.Stitch.MaxLength < 0 {
+			fmt.Fprintln(os.Stderr, "Stitch gap and maximal length can't be negative")
+			anyErr = true
+		}
+		if stitchColor, err = input.ColorExtractor(cfg.Stitch.Color); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid stitch gap color: %s\n", err)
+			anyErr = true
+		}
+	}
+
+	if cfg.Renditions.List != "" {
+		if renditions, err = input.RenditionsExtractor(cfg.Renditions.List, cfg.Image.ImgType); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid renditions (example: 320w,640w,1280w:webp): %s\n", err)
+			anyErr = true
+		}
+		if cfg.Sheet.Enabled || cfg.Tiles.Format != "" || cfg.Canvas.Size != "" {
+			fmt.Fprintln(os.Stderr, "Renditions can't be combined with contact sheet (--contact-sheet), tiles (--tiles) or canvas (--canvas)")
+			anyErr = true
+		}
+	}
+	if cfg.Renditions.Srcset != "" {
+		if cfg.Renditions.List == "" {
+			fmt.Fprintln(os.Stderr, "Srcset snippets (--srcset) require renditions (--renditions)")
+			anyErr = true
+		}
+		if cfg.Renditions.Srcset != "html" && cfg.Renditions.Srcset != "json" {
+			fmt.Fprintf(os.Stderr, "Unsupported srcset snippet format: %s\n", cfg.Renditions.Srcset)
+			anyErr = true
+		}
+	}
+
+	if cfg.Crop.Region != "" {
+		if cropRegion, err = input.CropExtractor(cfg.Crop.Region); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid crop region (example: 72,72,300,200): %s\n", err)
+			anyErr = true
+		}
+	}
+
+	if cfg.Rotate.Turn != "" && cfg.Rotate.Turn != "90" && cfg.Rotate.Turn != "180" &&
+		cfg.Rotate.Turn != "270" && cfg.Rotate.Turn != "auto" {
+		fmt.Fprintf(os.Stderr, "Unsupported rotation: %s\n", cfg.Rotate.Turn)
+		anyErr = true
+	}
+	if cfg.Split.Mode != "" && cfg.Split.Mode != "vertical" && cfg.Split.Mode != "horizontal" && cfg.Split.Mode != "auto" {
+		fmt.Fprintf(os.Stderr, "Unsupported spreads split mode: %s\n", cfg.Split.Mode)
+		anyErr = true
+	}
+
+	if cfg.Blank.Enabled && (cfg.Blank.Threshold < 0 || cfg.Blank.Threshold > 100) {
+		fmt.Fprintln(os.Stderr, "Blank page ink threshold must be in range 0-100")
+		anyErr = true
+	}
+	if (cfg.Blank.TextLayer || cfg.Blank.Move) && !cfg.Blank.Enabled {
+		fmt.Fprintln(os.Stderr, "Blank page options (--blank-text, --blank-move) require --skip-blank")
+		anyErr = true
+	}
+
+	if cfg.Words != "" && cfg.Words != "json" && cfg.Words != "hocr" && cfg.Words != "alto" {
+		fmt.Fprintf(os.Stderr, "Unsupported word boxes format: %s\n", cfg.Words)
+		anyErr = true
+	}
+	if cfg.Links || cfg.Words != "" {
+		if cfg.Sheet.Enabled || cfg.Tiles.Format != "" || cfg.Animate.Path != "" || cfg.Stitch.Mode != "" {
+			fmt.Fprintln(os.Stderr, "Link maps (--links) and word boxes (--words) can't be combined with contact sheet (--contact-sheet), "+
+				"tiles (--tiles), animation (--animate) or stitching (--stitch)")
+			anyErr = true
+		}
+		if cfg.Canvas.Size != "" || cfg.Rotate.Deskew {
+			fmt.Fprintln(os.Stderr, "Link maps (--links) and word boxes (--words) can't be combined with canvas (--canvas) or deskew (--deskew)")
+			anyErr = true
+		}
+	}
+
+	if cfg.OCR.Command != "" {
+		if err = input.OCRCommandValidator(cfg.OCR.Command); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid OCR command: %s. Error: %s\n", cfg.OCR.Command, err)
+			anyErr = true
+		}
+		if cfg.OCR.Timeout < 0 {
+			fmt.Fprintln(os.Stderr, "OCR timeout must be non-negative")
+			anyErr = true
+		}
+		if cfg.Sheet.Enabled || cfg.Tiles.Format != "" || cfg.Animate.Path != "" || cfg.Stitch.Mode != "" {
+			fmt.Fprintln(os.Stderr, "OCR (--ocr) can't be combined with contact sheet (--contact-sheet), "+
+				"tiles (--tiles), animation (--animate) or stitching (--stitch)")
+			anyErr = true
+		}
+		if cfg.Words == "hocr" {
+			fmt.Fprintln(os.Stderr, "OCR (--ocr) and hOCR word boxes (--words=hocr) write the same files, choose one of them")
+			anyErr = true
+		}
+	}
+	if (cfg.OCR.Force || pflag.CommandLine.Changed("ocr-timeout")) && cfg.OCR.Command == "" {
+		fmt.Fprintln(os.Stderr, "OCR options (--ocr-timeout, --ocr-force) require --ocr")
+		anyErr = true
+	}
+
+	if cfg.Exec.Page != "" {
+		if err = input.ExecPageCommandValidator(cfg.Exec.Page); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid page hook command: %s. Error: %s\n", cfg.Exec.Page, err)
+			anyErr = true
+		}
+		// combined outputs are written after all pages, there is no page file to run the hook on
+		if cfg.Sheet.Enabled || cfg.Sprite.Enabled || cfg.Tiles.Format != "" || cfg.Animate.Path != "" || cfg.Stitch.Mode != "" {
+			fmt.Fprintln(os.Stderr, "Page hook (--exec-page) can't be combined with contact sheet (--contact-sheet), "+
+				"sprite (--sprite), tiles (--tiles), animation (--animate) or stitching (--stitch)")
+			anyErr = true
+		}
+	}
+	if cfg.Exec.Done != "" {
+		if err = input.ExecDoneCommandValidator(cfg.Exec.Done); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid done hook command: %s. Error: %s\n", cfg.Exec.Done, err)
+			anyErr = true
+		}
+	}
+	if cfg.Exec.Jobs < 1 {
+		fmt.Fprintln(os.Stderr, "Number of hook jobs must be positive")
+		anyErr = true
+	}
+	if cfg.Exec.Timeout < 0 {
+		fmt.Fprintln(os.Stderr, "Hook timeout must be non-negative")
+		anyErr = true
+	}
+
+	if cfg.Dedupe.Enabled && (cfg.Dedupe.Distance < 0 || cfg.Dedupe.Distance > 64) {
+		fmt.Fprintln(os.Stderr, "Dedupe hash distance must be in range 0-64")
+		anyErr = true
+	}
+	if cfg.Dedupe.Hash != "ahash" && cfg.Dedupe.Hash != "dhash" && cfg.Dedupe.Hash != "phash" {
+		fmt.Fprintf(os.Stderr, "Unsupported perceptual hash: %s\n", cfg.Dedupe.Hash)
+		anyErr = true
+	}
+	if cfg.Dedupe.Group && !cfg.Dedupe.Enabled {
+		fmt.Fprintln(os.Stderr, "Grouping duplicates (--dedupe-group) requires --dedupe")
+		anyErr = true
+	}
+
+	if cfg.Trim.Tolerance < 0 || cfg.Trim.Tolerance > 255 {
+		fmt.Fprintln(os.Stderr, "Trim tolerance must be in range 0-255")
+		anyErr = true
+	}
+	if cfg.Trim.Padding < 0 {
+		fmt.Fprintln(os.Stderr, "Trim padding can't be negative")
+		anyErr = true
+	}
+
 	if anyErr {
 		os.Exit(1)
 	}
@@ -149,10 +746,71 @@ func main() {
 	fmt.Printf("Setting image format to %s, save folder: %s\n",
 		dsp.Fbg(cfg.Image.ImgType, cfg.Quiet),
 		dsp.Fbg(cfg.SaveDir, cfg.Quiet))
+	if cfg.Image.Background != "" {
+		fmt.Printf("Page background will be replaced with %s\n", dsp.Fbg(cfg.Image.Background, cfg.Quiet))
+	}
+	if cfg.Image.Invert {
+		fmt.Println("Page colors will be inverted")
+	} else if cfg.Image.DarkMode {
+		fmt.Println("Pages will be rendered in dark mode")
+	}
+	if cfg.Watermark.Text != "" {
+		fmt.Printf("Pages will be stamped with text watermark %s\n", dsp.Fbg(cfg.Watermark.Text, cfg.Quiet))
+	}
+	if cfg.Watermark.ImagePath != "" {
+		fmt.Printf("Pages will be stamped with image %s\n", dsp.Fbg(cfg.Watermark.ImagePath, cfg.Quiet))
+	}
+	if cfg.Caption.Template != "" {
+		fmt.Printf("Pages will be captioned with %s\n", dsp.Fbg(cfg.Caption.Template, cfg.Quiet))
+	}
+	if cfg.Canvas.Size != "" {
+		fmt.Printf("Pages will be placed on canvas %s\n", dsp.Fbg(cfg.Canvas.Size, cfg.Quiet))
+	}
+	if cfg.Renditions.List != "" {
+		fmt.Printf("Pages will be also saved as renditions %s\n", dsp.Fbg(cfg.Renditions.List, cfg.Quiet))
+	}
+	if cfg.Image.ColorMode != config.DefaultColorMode {
+		fmt.Printf("Output color mode: %s\n", dsp.Fbg(cfg.Image.ColorMode, cfg.Quiet))
+	}
 	if cfg.Pages != "" {
 		fmt.Printf("Selected pages will be extracted: %s\n",
 			dsp.Fbg(cfg.Pages, cfg.Quiet))
 	}
+	if cfg.Tiles.Format != "" {
+		fmt.Printf("Pages will be cut into %s tiles at %s DPI\n",
+			dsp.Fbg(cfg.Tiles.Format, cfg.Quiet),
+			dsp.Fbg(cfg.Tiles.DPI, cfg.Quiet))
+	}
+	if cfg.Rotate.Turn != "" {
+		fmt.Printf("Pages will be rotated %s\n", dsp.Fbg(cfg.Rotate.Turn, cfg.Quiet))
+	}
+	if cfg.Rotate.Deskew {
+		fmt.Println("Page skew will be corrected")
+	}
+	if cfg.Split.Mode != "" {
+		fmt.Printf("Two-page spreads will be split %s\n", dsp.Fbg(cfg.Split.Mode, cfg.Quiet))
+	}
+	if cfg.Blank.Enabled {
+		fmt.Printf("Blank pages with up to %s%% of ink will be skipped\n",
+			dsp.Fbg(strconv.FormatFloat(cfg.Blank.Threshold, 'f', -1, 64), cfg.Quiet))
+	}
+	if cfg.Dedupe.Enabled {
+		fmt.Printf("Pages within %s bits of %s distance will be deduplicated\n",
+			dsp.Fbg(strconv.Itoa(cfg.Dedupe.Distance), cfg.Quiet),
+			dsp.Fbg(cfg.Dedupe.Hash, cfg.Quiet))
+	}
+	if cfg.Crop.Region != "" {
+		fmt.Printf("Pages will be cropped to region %s\n", dsp.Fbg(cfg.Crop.Region, cfg.Quiet))
+	}
+	if cfg.Trim.Enabled || cfg.Trim.Uniform {
+		fmt.Printf("Page margins will be trimmed with tolerance %s\n",
+			dsp.Fbg(strconv.Itoa(cfg.Trim.Tolerance), cfg.Quiet))
+	}
+	if cfg.Sheet.Enabled {
+		fmt.Printf("Pages will be laid out on contact sheets, %s columns, cell size %s\n",
+			dsp.Fbg(strconv.Itoa(cfg.Sheet.Columns), cfg.Quiet),
+			dsp.Fbg(cfg.Sheet.CellSize, cfg.Quiet))
+	}
 
 	workDir, err := os.Getwd()
 	if err != nil {
@@ -189,19 +847,32 @@ func main() {
 	savePath := filepath.Join(workDir, cfg.SaveDir)
 	createPath := savePath
 	if cfg.Thumb.CreateThumbnails {
-		createPath = filepath.Join(createPath, config.ThumbnailsDir)
+		createPath = filepath.Join(createPath, cfg.Thumb.Dir)
 	}
 
 	err = os.MkdirAll(createPath, 0755)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if cfg.Blank.Move {
+		if err = os.MkdirAll(filepath.Join(savePath, config.BlankDir), 0755); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	thumbnails := extractor.Thumbnail{
 		IsActive:  cfg.Thumb.CreateThumbnails,
 		ScaleDown: cfg.Thumb.ThumbScaleDown,
 		SizeX:     thumbSizeX,
 		SizeY:     thumbSizeY,
+		ImgType:   strings.ToLower(cfg.Thumb.Format),
+		Quality:   cfg.Thumb.Quality,
+		Dir:       cfg.Thumb.Dir,
+		Name:      cfg.Thumb.Name,
+	}
+	if cfg.Canvas.ThumbSize != "" {
+		thumbnails.Canvas = canvas
+		thumbnails.Canvas.SizeX, thumbnails.Canvas.SizeY = thumbCanvasX, thumbCanvasY
 	}
 
 	page := extractor.Page{
@@ -214,8 +885,416 @@ func main() {
 		SizeX:      sizeX,
 		SizeY:      sizeY,
 		Thumbnails: thumbnails,
+		Color: extractor.ColorMode{
+			Mode:      cfg.Image.ColorMode,
+			Method:    cfg.Image.BWMethod,
+			Threshold: cfg.Image.BWThreshold,
+		},
+		Background: background,
+		Rotate: extractor.Rotate{
+			Turn:   cfg.Rotate.Turn,
+			Deskew: cfg.Rotate.Deskew,
+		},
+		Links: cfg.Links,
+		Words: cfg.Words,
+		Tone: extractor.Tone{
+			Invert:   cfg.Image.Invert,
+			DarkMode: cfg.Image.DarkMode,
+		},
+	}
+
+	if cfg.Links {
+		// go-fitz doesn't expose link rectangles, they are read from page annotations
+		objects, err := pdf.Open(filepath.Join(workDir, cfg.SourcePath))
+		if err == nil && objects.NumPage() != pageCount {
+			err = fmt.Errorf("found %d pages instead of %d", objects.NumPage(), pageCount)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Link annotations can't be read, link areas will be estimated from page text: %v\n", err)
+		} else {
+			page.Objects = objects
+		}
+	}
+
+	var contactSheet *sheet.ContactSheet
+	if cfg.Sheet.Enabled {
+		contactSheet = &sheet.ContactSheet{
+			Columns:    cfg.Sheet.Columns,
+			Rows:       cfg.Sheet.Rows,
+			CellX:      cellX,
+			CellY:      cellY,
+			Spacing:    cfg.Sheet.Spacing,
+			Background: sheetBg,
+			Captions:   cfg.Sheet.Captions,
+		}
+		page.Collector = contactSheet
+	}
+
+	var anim *animation.Animation
+	if cfg.Animate.Path != "" {
+		anim = &animation.Animation{
+			Delay:      time.Duration(cfg.Animate.Delay) * time.Millisecond,
+			Loop:       cfg.Animate.Loop,
+			Colors:     cfg.Animate.Colors,
+			Dither:     cfg.Animate.Dither,
+			Background: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		}
+		if background.Transparent {
+			anim.Background = color.RGBA{}
+		}
+		page.Collector = anim
+	}
+
+	var strip *sheet.Strip
+	if cfg.Stitch.Mode != "" {
+		strip = &sheet.Strip{
+			Vertical:  cfg.Stitch.Mode == "vertical",
+			Gap:       cfg.Stitch.Gap,
+			Separator: stitchColor,
+			MaxLength: imageutils.MaxDimension(cfg.Image.ImgType),
+		}
+		if cfg.Stitch.MaxLength > 0 {
+			strip.MaxLength = min(strip.MaxLength, cfg.Stitch.MaxLength)
+		}
+		page.Collector = strip
+	}
+
+	var sprite *sheet.Sprite
+	if cfg.Sprite.Enabled {
+		sprite = &sheet.Sprite{
+			Columns:    cfg.Sprite.Columns,
+			Rows:       cfg.Sprite.Rows,
+			MaxX:       spriteMaxX,
+			MaxY:       spriteMaxY,
+			Background: color.White,
+		}
+		// gaps between thumbnails match what thumbnails are made on: canvas or page background
+		if cfg.Canvas.ThumbSize != "" {
+			sprite.Background = canvas.Color
+		} else if background.Transparent {
+			sprite.Background = color.Transparent
+		} else if background.Color != nil {
+			sprite.Background = *background.Color
+		}
+		page.Thumbnails.Collector = sprite
+	}
+
+	if cfg.Tiles.Format != "" {
+		page.DPI = cfg.Tiles.DPI
+		page.Collector = &tiles.Pyramid{
+			Format:   cfg.Tiles.Format,
+			TileSize: cfg.Tiles.Size,
+			Overlap:  cfg.Tiles.Overlap,
+			ImgType:  cfg.Image.ImgType,
+			SavePath: savePath,
+			Prefix:   cfg.Prefix,
+			Postfix:  cfg.Postfix,
+			BaseURL:  cfg.Tiles.BaseURL,
+		}
+	}
+
+	for _, r := range renditions {
+		page.Renditions.Items = append(page.Renditions.Items, extractor.Rendition{Width: r.Width, ImgType: r.ImgType})
+	}
+	page.Renditions.Srcset = cfg.Renditions.Srcset
+
+	if cfg.Blank.Enabled {
+		page.Blank = extractor.Blank{
+			IsActive:  true,
+			Threshold: cfg.Blank.Threshold,
+			TextLayer: cfg.Blank.TextLayer,
+			Move:      cfg.Blank.Move,
+			Dir:       config.BlankDir,
+			Found:     &extractor.BlankPages{},
+		}
+	}
+
+	if cfg.OCR.Command != "" {
+		ocrCommand, err := command.Parse(cfg.OCR.Command)
+		if err != nil {
+			log.Fatal(err)
+		}
+		page.OCR = extractor.OCR{
+			Engine: ocr.Command{
+				Template:   ocrCommand,
+				Timeout:    time.Duration(cfg.OCR.Timeout) * time.Second,
+				Extensions: []string{".txt", ".hocr"},
+			},
+			MinText: config.OCRMinText,
+			Force:   cfg.OCR.Force,
+		}
+	}
+
+	// output of hook commands is kept in a log inside output folder
+	var doneHook *command.Hook
+	if cfg.Exec.Page != "" || cfg.Exec.Done != "" {
+		execLog, err := os.Create(filepath.Join(savePath, config.ExecLogName))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer execLog.Close()
+		timeout := time.Duration(cfg.Exec.Timeout) * time.Second
+		if cfg.Exec.Page != "" {
+			pageCommand, err := command.Parse(cfg.Exec.Page)
+			if err != nil {
+				log.Fatal(err)
+			}
+			page.Exec = command.NewHook(pageCommand, timeout, cfg.Exec.Jobs, execLog)
+		}
+		if cfg.Exec.Done != "" {
+			doneCommand, err := command.Parse(cfg.Exec.Done)
+			if err != nil {
+				log.Fatal(err)
+			}
+			doneHook = command.NewHook(doneCommand, timeout, 1, execLog)
+		}
+	}
+
+	// hashes of deduplicated pages are recorded in manifest, done hook gets its path
+	if cfg.Manifest || cfg.Dedupe.Enabled || cfg.Exec.Done != "" {
+		page.Manifest = &manifest.Manifest{Source: cfg.SourcePath}
+	}
+
+	page.Watermark = extractor.Watermark{
+		Text:          cfg.Watermark.Text,
+		TextSize:      cfg.Watermark.TextSize,
+		TextColor:     watermarkColor,
+		TextOpacity:   cfg.Watermark.TextOpacity,
+		TextAngle:     cfg.Watermark.TextAngle,
+		TextPosition:  imageutils.Anchor(cfg.Watermark.TextPosition),
+		ImagePosition: imageutils.Anchor(cfg.Watermark.ImagePosition),
+		ImageScale:    cfg.Watermark.ImageScale,
+		ImageOpacity:  cfg.Watermark.ImageOpacity,
+		Thumbnails:    cfg.Watermark.Thumbnails,
+	}
+	if cfg.Canvas.Size != "" {
+		page.Canvas = canvas
+		page.Canvas.SizeX, page.Canvas.SizeY = canvasX, canvasY
+	}
+
+	if cfg.Split.Mode != "" {
+		page.Split, err = extractor.NewSplit(doc, cfg.Split.Mode, cfg.Split.Gutter, cfg.Split.RTL)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	page.Caption = extractor.Caption{
+		Template:   cfg.Caption.Template,
+		DocName:    strings.TrimSuffix(filepath.Base(cfg.SourcePath), filepath.Ext(cfg.SourcePath)),
+		Total:      pageCount,
+		Position:   cfg.Caption.Position,
+		Align:      imageutils.Anchor(cfg.Caption.Align),
+		Size:       cfg.Caption.Size,
+		Color:      captionColor,
+		Background: captionBg,
+	}
+
+	if cfg.Split.Mode != "" {
+		page.Caption.Total = page.Split.Total
+	}
+
+	if cfg.Watermark.FontPath != "" {
+		page.Watermark.Font, err = imageutils.LoadFont(cfg.Watermark.FontPath)
+		if err != nil {
+			log.Fatalf("Failed to load watermark font %s: %s", cfg.Watermark.FontPath, err)
+		}
+	}
+	if cfg.Watermark.ImagePath != "" {
+		page.Watermark.Image, err = imageutils.Load(cfg.Watermark.ImagePath)
+		if err != nil {
+			log.Fatalf("Failed to load watermark image %s: %s", cfg.Watermark.ImagePath, err)
+		}
+	}
+
+	if cfg.Crop.Region != "" {
+		page.Crop.Region = &cropRegion
+	}
+	if cfg.Crop.SpecPath != "" {
+		page.Crop.Pages, err = readCropSpec(cfg.Crop.SpecPath, pageCount)
+		if err != nil {
+			log.Fatalf("Invalid crop spec %s: %s", cfg.Crop.SpecPath, err)
+		}
+	}
+
+	if cfg.Trim.Enabled || cfg.Trim.Uniform {
+		page.Trim = extractor.Trim{
+			IsActive:  true,
+			Tolerance: cfg.Trim.Tolerance,
+			Padding:   cfg.Trim.Padding,
+		}
+	}
+
+	if cfg.Trim.Uniform {
+		fmt.Println("Measuring content bounds of pages...")
+		bounds := &extractor.ContentBounds{Tolerance: page.Trim.Tolerance}
+		if failed := runJobs(page.Measure(bounds), pagesToExtract, cfg.WorkersNum, cfg.Quiet); failed > 0 {
+			log.Fatalf("Failed to measure content bounds of %d page(s)", failed)
+		}
+		page.Trim.Box = bounds.Union()
+	}
+
+	if cfg.Dedupe.Enabled {
+		fmt.Println("Hashing pages...")
+		hashes := &extractor.Hashes{Algorithm: cfg.Dedupe.Hash}
+		if failed := runJobs(page.Measure(hashes), pagesToExtract, cfg.WorkersNum, cfg.Quiet); failed > 0 {
+			log.Fatalf("Failed to hash %d page(s)", failed)
+		}
+		page.Dedupe = extractor.Dedupe{
+			DuplicateOf: hashes.Duplicates(cfg.Dedupe.Distance),
+			Drop:        !cfg.Dedupe.Group,
+		}
+		for pageNum, hash := range hashes.Hashes() {
+			page.Manifest.Update(pageNum, func(entry *manifest.PageEntry) {
+				entry.Hash = fmt.Sprintf("%016x", hash)
+				if kept, ok := page.Dedupe.DuplicateOf[pageNum]; ok {
+					entry.DuplicateOf = kept + 1
+				}
+			})
+		}
+	}
+
+	failed := runJobs(page, pagesToExtract, cfg.WorkersNum, cfg.Quiet)
+
+	if contactSheet != nil {
+		sheets, err := contactSheet.Sheets()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, sheetImg := range sheets {
+			sheetFName := fmt.Sprintf("%s_%03d.%s", config.ContactSheetName, i+1, cfg.Image.ImgType)
+			err = imageutils.Save(filepath.Join(savePath, sheetFName), cfg.Image.ImgType, page.Convert(cfg.Image.ImgType, sheetImg))
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		fmt.Printf("Saved %s contact sheet(s)\n", dsp.Fbg(strconv.Itoa(len(sheets)), cfg.Quiet))
+	}
+
+	if strip != nil {
+		stripParts := 0
+		err = strip.Save(func(part, parts int, img *image.RGBA) error {
+			stripFName := fmt.Sprintf("%s.%s", config.StitchName, cfg.Image.ImgType)
+			if parts > 1 {
+				stripFName = fmt.Sprintf("%s_%03d.%s", config.StitchName, part+1, cfg.Image.ImgType)
+			}
+			stripParts = parts
+			return imageutils.Save(filepath.Join(savePath, stripFName), cfg.Image.ImgType, page.Convert(cfg.Image.ImgType, img))
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Saved stitched image in %s part(s)\n", dsp.Fbg(strconv.Itoa(stripParts), cfg.Quiet))
 	}
 
+	if anim != nil {
+		if err = anim.Save(filepath.Join(savePath, cfg.Animate.Path)); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Saved animation %s\n", dsp.Fbg(cfg.Animate.Path, cfg.Quiet))
+	}
+
+	if sprite != nil {
+		if err = saveSprite(sprite, &page, cfg); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if blankPages := page.Blank.Found.Pages(); len(blankPages) > 0 {
+		pageList := make([]string, len(blankPages))
+		for i, pageNum := range blankPages {
+			pageList[i] = strconv.Itoa(pageNum + 1)
+		}
+		action := "skipped"
+		if cfg.Blank.Move && page.Collector == nil {
+			action = "moved to " + config.BlankDir
+		}
+		fmt.Printf("Blank page(s) %s: %s\n", action, dsp.Fbg(strings.Join(pageList, ", "), cfg.Quiet))
+	}
+
+	if len(page.Dedupe.DuplicateOf) > 0 {
+		action := "dropped"
+		if cfg.Dedupe.Group {
+			action = "grouped"
+		}
+		fmt.Printf("Duplicate page(s) %s: %s\n", action,
+			dsp.Fbg(strconv.Itoa(len(page.Dedupe.DuplicateOf)), cfg.Quiet))
+	}
+
+	if err = page.Manifest.Save(filepath.Join(savePath, manifest.FileName)); err != nil {
+		log.Fatal(err)
+	}
+
+	if doneHook != nil {
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "Done hook skipped: %d page(s) failed\n", failed)
+		} else if err = doneHook.Run(context.Background(), map[string]string{
+			"{manifest}": filepath.Join(savePath, manifest.FileName),
+			"{dir}":      savePath,
+		}); err != nil {
+			log.Fatalf("Done hook failed: %v", err)
+		}
+	}
+
+	if failed == 0 {
+		fmt.Println("Finished extraction")
+	}
+}
+
+// saveSprite saves sprite sheets into thumbnails folder together with the map of page rectangles
+func saveSprite(sprite *sheet.Sprite, page *extractor.Page, cfg config.Config) error {
+	imgType := page.Thumbnails.ImgType
+	if imgType == "" {
+		imgType = page.ImgType
+	}
+	spriteSheets := sprite.Sheets()
+	files := make([]string, len(spriteSheets))
+	for i, spriteSheet := range spriteSheets {
+		files[i] = fmt.Sprintf("%s_%03d.%s", config.SpriteName, i+1, imgType)
+		err := imageutils.SaveQuality(filepath.Join(page.SavePath, page.Thumbnails.Dir, files[i]),
+			imgType, page.Convert(imgType, spriteSheet.Image), page.Thumbnails.Quality)
+		if err != nil {
+			return err
+		}
+		for _, tile := range spriteSheet.Tiles {
+			page.Manifest.AddFile(tile.Page, filepath.Join(page.Thumbnails.Dir, files[i]))
+		}
+	}
+
+	f, err := os.Create(filepath.Join(page.SavePath, page.Thumbnails.Dir, config.SpriteName+"."+cfg.Sprite.Map))
+	if err != nil {
+		return err
+	}
+	interval := time.Duration(cfg.Sprite.Interval * float64(time.Second))
+	if err = sheet.WriteSpriteMap(f, cfg.Sprite.Map, files, spriteSheets, interval); err != nil {
+		f.Close()
+		return err
+	}
+	fmt.Printf("Saved %s sprite sheet(s)\n", dsp.Fbg(strconv.Itoa(len(spriteSheets)), cfg.Quiet))
+	return f.Close()
+}
+
+// readCropSpec reads per page crop regions and keys them by zero-based page number
+func readCropSpec(path string, pageCount int) (map[int]imageutils.Region, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	regions, err := input.CropSpecExtractor(f, pageCount)
+	if err != nil {
+		return nil, err
+	}
+	pages := make(map[int]imageutils.Region, len(regions))
+	for pageNum, region := range regions {
+		pages[pageNum-1] = region
+	}
+	return pages, nil
+}
+
+// runJobs extracts pages with a pool of workers and returns number of failed jobs
+func runJobs(page extractor.Page, pagesToExtract []int, workersNum int, quiet bool) int {
 	var wg sync.WaitGroup
 	numJobs := len(pagesToExtract)
 	jobs := make(chan extractor.Job, numJobs)
@@ -223,11 +1302,11 @@ func main() {
 	done := make(chan struct{}, numJobs)
 
 	var bar *progressbar.ProgressBar
-	if !cfg.Quiet {
+	if !quiet {
 		bar = progressbar.Default(int64(numJobs))
 	}
 
-	for w := 1; w <= cfg.WorkersNum; w++ {
+	for w := 1; w <= workersNum; w++ {
 		wg.Add(1)
 		go extractor.Worker(w, jobs, jobErrors, done, &wg)
 	}
@@ -240,9 +1319,8 @@ func main() {
 	go func() {
 		for i := 0; i < numJobs; i++ {
 			<-done
-			if !cfg.Quiet {
-				err = bar.Add(1)
-				if err != nil {
+			if !quiet {
+				if err := bar.Add(1); err != nil {
 					fmt.Fprintf(os.Stderr, "Progress bar encountered problem: %s\n", err)
 				}
 			}
@@ -251,22 +1329,21 @@ func main() {
 
 	wg.Wait()
 
-	if !cfg.Quiet {
-		err = bar.Finish()
-		if err != nil {
+	if !quiet {
+		if err := bar.Finish(); err != nil {
 			fmt.Fprintf(os.Stderr, "Progress bar encountered problem: %s\n", err)
 		}
 	}
 
 	close(jobErrors)
 
+	failed := 0
 	for jobErr := range jobErrors {
 		if jobErr.Err != nil {
 			log.Printf("Worker %d failed: %v", jobErr.WorkerID, jobErr.Err)
+			failed++
 		}
 	}
 
-	if len(jobErrors) == 0 {
-		fmt.Println("Finished extraction")
-	}
+	return failed
 }
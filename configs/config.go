@@ -1,11 +1,129 @@
 package config
 
 const (
-	ImgScaleDownDefault   = 1.0
-	ThumbScaleDownDefault = 10.0
-	DefaultFilenamePrefix = "page"
-	DefaultImgFormat      = "png"
-	ThumbnailsDir         = "thumbnails"
+	ImgScaleDownDefault     = 1.0
+	ThumbScaleDownDefault   = 10.0
+	DefaultFilenamePrefix   = "page"
+	DefaultImgFormat        = "png"
+	DefaultColorMode        = "rgb"
+	DefaultBilevelMethod    = "otsu"
+	BilevelThresholdDefault = 128
+	TransparentBackground   = "transparent"
+	ThumbnailsDir           = "thumbnails"
+	ThumbNameDefault        = "thumbnail_{page}"
+	ImgQualityDefault       = 75
+)
+
+// contact sheet defaults
+const (
+	SheetColumnsDefault    = 4
+	SheetRowsDefault       = 5
+	SheetCellDefault       = "240x320"
+	SheetSpacingDefault    = 10
+	SheetBackgroundDefault = "#ffffff"
+	ContactSheetName       = "contact_sheet"
+)
+
+// thumbnail sprite defaults
+const (
+	SpriteColumnsDefault  = 10
+	SpriteMaxSizeDefault  = "4096x4096"
+	SpriteMapDefault      = "json"
+	SpriteIntervalDefault = 1.0
+	SpriteName            = "sprite"
+)
+
+// animation defaults
+const (
+	AnimDelayDefault  = 1000
+	AnimColorsDefault = 256
+)
+
+// strip defaults
+const (
+	StitchColorDefault = "#ffffff"
+	StitchName         = "strip"
+)
+
+// blank page defaults
+const (
+	BlankThresholdDefault = 0.5
+	BlankDir              = "blank"
+)
+
+// dedupe defaults
+const (
+	DedupeDistanceDefault = 5
+	DedupeHashDefault     = "phash"
+)
+
+// diff subcommand defaults
+const (
+	DiffDPIDefault       = 150.0
+	DiffThresholdDefault = 32
+	DiffColorDefault     = "#ff0000"
+	DiffDistanceDefault  = 10
+	// pages are rendered at low resolution for alignment
+	DiffAlignDPI = 24.0
+)
+
+// images subcommand defaults
+const (
+	ImagesNameDefault = "{prefix}{page}{postfix}_img{index}"
+)
+
+// OCR defaults
+const (
+	OCRCommandDefault = "tesseract {image} {output} txt hocr"
+	OCRTimeoutDefault = 120
+	// pages with fewer non-space characters in text layer are recognized
+	OCRMinText = 20
+)
+
+// hook command defaults
+const (
+	ExecTimeoutDefault = 60
+	ExecLogName        = "exec.log"
+)
+
+// tile pyramid defaults
+const (
+	TileSizeDefault    = 256
+	TileOverlapDefault = 1
+	TileDPIDefault     = 300.0
+)
+
+// watermark defaults
+const (
+	WatermarkTextSizeDefault      = 8.0
+	WatermarkTextColorDefault     = "#808080"
+	WatermarkTextOpacityDefault   = 0.3
+	WatermarkTextPositionDefault  = "center"
+	WatermarkImageScaleDefault    = 20.0
+	WatermarkImageOpacityDefault  = 0.5
+	WatermarkImagePositionDefault = "bottom-right"
+)
+
+// caption defaults
+const (
+	CaptionPositionDefault   = "bottom"
+	CaptionAlignDefault      = "center"
+	CaptionSizeDefault       = 2.5
+	CaptionColorDefault      = "#000000"
+	CaptionBackgroundDefault = "#ffffff"
+)
+
+// canvas defaults
+const (
+	CanvasPadColorDefault    = "#ffffff"
+	CanvasAlignDefault       = "center"
+	CanvasBorderColorDefault = "#cccccc"
+)
+
+// trimming defaults
+const (
+	TrimToleranceDefault = 10
+	TrimPaddingDefault   = 0
 )
 
 type Config struct {
@@ -18,13 +136,165 @@ type Config struct {
 		ImgSize      string
 		ImgScaleDown float64
 		ImgType      string
+		ColorMode    string
+		BWMethod     string
+		BWThreshold  int
+		Background   string
+		Invert       bool
+		DarkMode     bool
 	}
 	Thumb struct {
 		CreateThumbnails bool
 		ThumbScaleDown   float64
 		ThumbnailsSize   string
+		Format           string
+		Quality          int
+		Dir              string
+		Name             string
+	}
+	Sheet struct {
+		Enabled    bool
+		Columns    int
+		Rows       int
+		CellSize   string
+		Spacing    int
+		Background string
+		Captions   bool
+	}
+	Sprite struct {
+		Enabled  bool
+		Columns  int
+		Rows     int
+		MaxSize  string
+		Map      string
+		Interval float64
+	}
+	Animate struct {
+		Path   string
+		Delay  int
+		Loop   int
+		Colors int
+		Dither bool
+	}
+	Stitch struct {
+		Mode      string
+		Gap       int
+		Color     string
+		MaxLength int
+	}
+	Tiles struct {
+		Format  string
+		Size    int
+		Overlap int
+		DPI     float64
+		BaseURL string
+	}
+	Crop struct {
+		Region   string
+		SpecPath string
+	}
+	Rotate struct {
+		Turn   string
+		Deskew bool
+	}
+	Split struct {
+		Mode   string
+		Gutter bool
+		RTL    bool
+	}
+	Blank struct {
+		Enabled   bool
+		Threshold float64
+		TextLayer bool
+		Move      bool
 	}
+	Dedupe struct {
+		Enabled  bool
+		Distance int
+		Hash     string
+		Group    bool
+	}
+	Trim struct {
+		Enabled   bool
+		Uniform   bool
+		Tolerance int
+		Padding   int
+	}
+	Watermark struct {
+		Text          string
+		FontPath      string
+		TextSize      float64
+		TextColor     string
+		TextOpacity   float64
+		TextAngle     float64
+		TextPosition  string
+		ImagePath     string
+		ImageScale    float64
+		ImageOpacity  float64
+		ImagePosition string
+		Thumbnails    bool
+	}
+	Caption struct {
+		Template   string
+		Position   string
+		Align      string
+		Size       float64
+		Color      string
+		Background string
+	}
+	Canvas struct {
+		Size        string
+		ThumbSize   string
+		PadColor    string
+		Align       string
+		Margin      int
+		Border      int
+		BorderColor string
+		Shadow      bool
+	}
+	Renditions struct {
+		List   string
+		Srcset string
+	}
+	OCR struct {
+		Command string
+		Timeout int
+		Force   bool
+	}
+	Exec struct {
+		Page    string
+		Done    string
+		Jobs    int
+		Timeout int
+	}
+	Links       bool
+	Words       string
+	Manifest    bool
 	WorkersNum  int
 	VersionFlag bool
 	Quiet       bool
 }
+
+// DiffConfig holds settings of diff subcommand
+type DiffConfig struct {
+	SaveDir    string
+	ImgType    string
+	DPI        float64
+	Threshold  int
+	Color      string
+	Align      bool
+	Distance   int
+	WorkersNum int
+	Quiet      bool
+}
+
+// ImagesConfig holds settings of images subcommand
+type ImagesConfig struct {
+	SourcePath string
+	SaveDir    string
+	Pages      string
+	Prefix     string
+	Postfix    string
+	Name       string
+	Quiet      bool
+}
@@ -3,6 +3,7 @@ module github.com/dmikhr/pdfjuicer
 go 1.23.0
 
 require (
+	github.com/HugoSmits86/nativewebp v0.9.3
 	github.com/gen2brain/go-fitz v1.24.14
 	github.com/schollz/progressbar/v3 v3.18.0
 	github.com/spf13/pflag v1.0.6
@@ -16,4 +17,5 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.32.0 // indirect
 	golang.org/x/term v0.31.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
 )
@@ -0,0 +1,109 @@
+package animation
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when animation file extension is not gif, png, apng or webp
+	ErrUnsupportedFormat = errors.New("unsupported animation format")
+	// ErrNoFrames is returned when no page was rendered for animation
+	ErrNoFrames = errors.New("no frames to animate")
+)
+
+// Animation collects rendered pages as frames of a slideshow
+type Animation struct {
+	Delay time.Duration
+	// Loop is number of plays, 0 means infinite
+	Loop int
+	// Colors limits GIF palette size
+	Colors int
+	// Dither enables Floyd–Steinberg dithering of GIF frames
+	Dither bool
+	// Background fills canvas around frames which differ in size from the first one
+	Background color.RGBA
+
+	mu     sync.Mutex
+	frames map[int]*image.RGBA
+}
+
+// Collect stores page as animation frame
+func (a *Animation) Collect(pageNum int, img *image.RGBA) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.frames == nil {
+		a.frames = make(map[int]*image.RGBA)
+	}
+	a.frames[pageNum] = img
+	return nil
+}
+
+// Save encodes collected frames in page order, format is chosen by file extension
+func (a *Animation) Save(path string) error {
+	var encode func(w io.Writer, frames []*image.RGBA) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gif":
+		encode = a.encodeGIF
+	case ".png", ".apng":
+		encode = a.encodeAPNG
+	case ".webp":
+		encode = a.encodeWebP
+	default:
+		return ErrUnsupportedFormat
+	}
+
+	frames := a.sortedFrames()
+	if len(frames) == 0 {
+		return ErrNoFrames
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = encode(f, frames); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// sortedFrames returns frames in page order, fitted into the size of the first frame
+func (a *Animation) sortedFrames() []*image.RGBA {
+	pageNums := make([]int, 0, len(a.frames))
+	for pageNum := range a.frames {
+		pageNums = append(pageNums, pageNum)
+	}
+	sort.Ints(pageNums)
+
+	frames := make([]*image.RGBA, 0, len(pageNums))
+	var size image.Point
+	for _, pageNum := range pageNums {
+		frame := a.frames[pageNum]
+		if len(frames) == 0 {
+			size = frame.Bounds().Size()
+		} else if frame.Bounds().Size() != size {
+			fitted := imageutils.Fit(frame, size.X, size.Y)
+			canvas := image.NewRGBA(image.Rectangle{Max: size})
+			draw.Draw(canvas, canvas.Bounds(), image.NewUniform(a.Background), image.Point{}, draw.Src)
+			pos := imageutils.Anchor("center").Place(canvas.Bounds(), fitted.Bounds().Size(), 0)
+			imageutils.Overlay(canvas, fitted, pos, 1)
+			frame = canvas
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
@@ -0,0 +1,245 @@
+package animation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// animInfo is what a player reads from animation: frames, delay of every frame and number of plays
+type animInfo struct {
+	frames int
+	delays []time.Duration
+	plays  int
+}
+
+type animationTestCase struct {
+	comment  string
+	fname    string
+	frames   int
+	delay    time.Duration
+	loop     int
+	expected animInfo
+}
+
+var AnimationTestCase = []animationTestCase{
+	{
+		comment:  "GIF infinite loop",
+		fname:    "anim.gif",
+		frames:   3,
+		delay:    500 * time.Millisecond,
+		expected: animInfo{frames: 3, delays: repeat(500*time.Millisecond, 3)},
+	},
+	{
+		comment:  "GIF played twice",
+		fname:    "anim.gif",
+		frames:   2,
+		delay:    1234 * time.Millisecond,
+		loop:     2,
+		expected: animInfo{frames: 2, delays: repeat(1230*time.Millisecond, 2), plays: 2},
+	},
+	{
+		comment:  "GIF delay shorter than a hundredth of a second",
+		fname:    "anim.gif",
+		frames:   2,
+		delay:    4 * time.Millisecond,
+		expected: animInfo{frames: 2, delays: repeat(10*time.Millisecond, 2)},
+	},
+	{
+		comment:  "APNG",
+		fname:    "anim.png",
+		frames:   3,
+		delay:    1234 * time.Millisecond,
+		loop:     1,
+		expected: animInfo{frames: 3, delays: repeat(1234*time.Millisecond, 3), plays: 1},
+	},
+	{
+		comment:  "APNG with apng extension",
+		fname:    "anim.apng",
+		frames:   1,
+		delay:    time.Second,
+		expected: animInfo{frames: 1, delays: repeat(time.Second, 1)},
+	},
+	{
+		comment:  "WebP",
+		fname:    "anim.webp",
+		frames:   4,
+		delay:    250 * time.Millisecond,
+		loop:     3,
+		expected: animInfo{frames: 4, delays: repeat(250*time.Millisecond, 4), plays: 3},
+	},
+}
+
+func TestAnimationSave(t *testing.T) {
+	for _, tc := range AnimationTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			a := &Animation{Delay: tc.delay, Loop: tc.loop, Colors: 16}
+			// frames are collected out of order like workers deliver them, the last one differs in size
+			for pageNum := tc.frames - 1; pageNum >= 0; pageNum-- {
+				size := 20
+				if pageNum == tc.frames-1 && tc.frames > 1 {
+					size = 10
+				}
+				if err := a.Collect(pageNum, image.NewRGBA(image.Rect(0, 0, size*2, size))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			path := filepath.Join(t.TempDir(), tc.fname)
+			if err := a.Save(path); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got animInfo
+			switch filepath.Ext(tc.fname) {
+			case ".gif":
+				got, err = readGIF(data)
+			case ".webp":
+				got, err = readWebP(data)
+			default:
+				got, err = readAPNG(data)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.frames != tc.expected.frames || got.plays != tc.expected.plays ||
+				!equalDelays(got.delays, tc.expected.delays) {
+				t.Errorf("%s test. want: %+v, got: %+v", tc.comment, tc.expected, got)
+			}
+		})
+	}
+}
+
+type saveErrorTestCase struct {
+	comment     string
+	fname       string
+	frames      int
+	expectError error
+}
+
+var SaveErrorTestCase = []saveErrorTestCase{
+	{
+		comment:     "Unsupported extension",
+		fname:       "anim.mp4",
+		frames:      1,
+		expectError: ErrUnsupportedFormat,
+	},
+	{
+		comment:     "No frames",
+		fname:       "anim.gif",
+		expectError: ErrNoFrames,
+	},
+}
+
+func TestAnimationSaveErrors(t *testing.T) {
+	for _, tc := range SaveErrorTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			a := &Animation{Delay: time.Second, Colors: 16}
+			for pageNum := 0; pageNum < tc.frames; pageNum++ {
+				if err := a.Collect(pageNum, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := a.Save(filepath.Join(t.TempDir(), tc.fname))
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+// readGIF decodes GIF, delays are stored in hundredths of a second and loop count counts repeats
+func readGIF(data []byte) (animInfo, error) {
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return animInfo{}, err
+	}
+	info := animInfo{frames: len(anim.Image)}
+	for _, delay := range anim.Delay {
+		info.delays = append(info.delays, time.Duration(delay)*10*time.Millisecond)
+	}
+	switch anim.LoopCount {
+	case -1:
+		info.plays = 1
+	case 0:
+		info.plays = 0
+	default:
+		info.plays = anim.LoopCount + 1
+	}
+	return info, nil
+}
+
+// readAPNG walks PNG chunks reading acTL and fcTL
+func readAPNG(data []byte) (animInfo, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return animInfo{}, errors.New("no png signature")
+	}
+	var info animInfo
+	for rest := data[len(pngSignature):]; len(rest) >= 12; {
+		length := int(binary.BigEndian.Uint32(rest))
+		chunkType, chunk := string(rest[4:8]), rest[8:8+length]
+		switch chunkType {
+		case "acTL":
+			info.plays = int(binary.BigEndian.Uint32(chunk[4:]))
+		case "fcTL":
+			info.frames++
+			num, den := binary.BigEndian.Uint16(chunk[20:]), binary.BigEndian.Uint16(chunk[22:])
+			info.delays = append(info.delays, time.Duration(num)*time.Second/time.Duration(den))
+		}
+		rest = rest[12+length:]
+	}
+	return info, nil
+}
+
+// readWebP walks RIFF chunks reading ANIM and ANMF
+func readWebP(data []byte) (animInfo, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return animInfo{}, errors.New("no webp header")
+	}
+	var info animInfo
+	for rest := data[12:]; len(rest) >= 8; {
+		length := int(binary.LittleEndian.Uint32(rest[4:]))
+		chunkType, chunk := string(rest[:4]), rest[8:8+length]
+		switch chunkType {
+		case "ANIM":
+			info.plays = int(binary.LittleEndian.Uint16(chunk[4:]))
+		case "ANMF":
+			info.frames++
+			duration := int(chunk[12]) | int(chunk[13])<<8 | int(chunk[14])<<16
+			info.delays = append(info.delays, time.Duration(duration)*time.Millisecond)
+		}
+		// chunks are padded to even size
+		rest = rest[8+length+length%2:]
+	}
+	return info, nil
+}
+
+func repeat(delay time.Duration, n int) []time.Duration {
+	delays := make([]time.Duration, n)
+	for i := range delays {
+		delays[i] = delay
+	}
+	return delays
+}
+
+func equalDelays(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package animation
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// encodeAPNG writes frames as animated PNG, the standard library has no APNG encoder,
+// so 8-bit RGBA frames are compressed here and wrapped into APNG chunks
+func (a *Animation) encodeAPNG(w io.Writer, frames []*image.RGBA) error {
+	size := frames[0].Bounds().Size()
+	if _, err := w.Write(pngSignature); err != nil {
+		return err
+	}
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(size.X))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(size.Y))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // truecolor with alpha
+	if err := writeChunk(w, "IHDR", ihdr); err != nil {
+		return err
+	}
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+	binary.BigEndian.PutUint32(actl[4:], uint32(a.Loop))
+	if err := writeChunk(w, "acTL", actl); err != nil {
+		return err
+	}
+
+	// delay is stored as a fraction of seconds, ms/1000
+	delay := uint16(min(a.Delay.Milliseconds(), 0xffff))
+	var seq uint32
+	for i, frame := range frames {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(size.X))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(size.Y))
+		binary.BigEndian.PutUint16(fctl[20:], delay)
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		// dispose_op and blend_op are left 0: every frame replaces the whole canvas
+		if err := writeChunk(w, "fcTL", fctl); err != nil {
+			return err
+		}
+		seq++
+
+		data, err := compressFrame(frame)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			err = writeChunk(w, "IDAT", data)
+		} else {
+			fdat := binary.BigEndian.AppendUint32(make([]byte, 0, len(data)+4), seq)
+			err = writeChunk(w, "fdAT", append(fdat, data...))
+			seq++
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeChunk(w, "IEND", nil)
+}
+
+// compressFrame encodes frame as non-premultiplied RGBA scanlines with Sub filter
+func compressFrame(frame *image.RGBA) ([]byte, error) {
+	nrgba := image.NewNRGBA(frame.Bounds().Sub(frame.Bounds().Min))
+	draw.Draw(nrgba, nrgba.Bounds(), frame, frame.Bounds().Min, draw.Src)
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	rowLen := nrgba.Bounds().Dx() * 4
+	line := make([]byte, rowLen+1)
+	for y := 0; y < nrgba.Bounds().Dy(); y++ {
+		row := nrgba.Pix[y*nrgba.Stride : y*nrgba.Stride+rowLen]
+		line[0] = 1 // Sub filter: difference with the pixel on the left
+		for i := range row {
+			if i < 4 {
+				line[i+1] = row[i]
+			} else {
+				line[i+1] = row[i] - row[i-4]
+			}
+		}
+		if _, err := zw.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeChunk writes PNG chunk: length, type, data and CRC of type and data
+func writeChunk(w io.Writer, chunkType string, data []byte) error {
+	header := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	header = append(header, chunkType...)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	for _, part := range [][]byte{header, data, binary.BigEndian.AppendUint32(nil, crc.Sum32())} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
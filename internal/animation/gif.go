@@ -0,0 +1,46 @@
+package animation
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+
+	"golang.org/x/image/draw"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// encodeGIF quantizes every frame to its own palette, GIF has no partial transparency
+// so frames are flattened onto white
+func (a *Animation) encodeGIF(w io.Writer, frames []*image.RGBA) error {
+	anim := gif.GIF{LoopCount: gifLoopCount(a.Loop)}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	// delay is stored in hundredths of a second, zero would mean no delay at all
+	delay := max(int(a.Delay.Milliseconds()/10), 1)
+
+	for _, frame := range frames {
+		frame = imageutils.Flatten(frame, white)
+		paletted := image.NewPaletted(frame.Bounds(), imageutils.MedianCut(frame, a.Colors))
+		if a.Dither {
+			draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, frame.Bounds().Min)
+		} else {
+			draw.Draw(paletted, frame.Bounds(), frame, frame.Bounds().Min, draw.Src)
+		}
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	return gif.EncodeAll(w, &anim)
+}
+
+// gifLoopCount converts number of plays to GIF loop count which counts repeats
+func gifLoopCount(plays int) int {
+	switch plays {
+	case 0:
+		return 0
+	case 1:
+		return -1
+	}
+	return plays - 1
+}
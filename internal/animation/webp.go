@@ -0,0 +1,104 @@
+package animation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// errWebPFrame is returned when encoded frame is not a RIFF WebP container
+var errWebPFrame = errors.New("unexpected webp frame encoding")
+
+// encodeWebP writes frames as animated WebP. Frames are encoded lossless one by one
+// and their bitstreams are wrapped into ANMF chunks of an extended WebP container
+func (a *Animation) encodeWebP(w io.Writer, frames []*image.RGBA) error {
+	size := frames[0].Bounds().Size()
+
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+
+	var flags byte = 0x02 // animation
+	for _, frame := range frames {
+		if !frame.Opaque() {
+			flags |= 0x10 // alpha
+			break
+		}
+	}
+	vp8x := []byte{flags, 0, 0, 0}
+	vp8x = appendUint24(vp8x, size.X-1)
+	vp8x = appendUint24(vp8x, size.Y-1)
+	writeRIFFChunk(&body, "VP8X", vp8x)
+
+	// background color hint in BGRA order and number of plays
+	anim := []byte{0xff, 0xff, 0xff, 0xff}
+	anim = binary.LittleEndian.AppendUint16(anim, uint16(min(a.Loop, 0xffff)))
+	writeRIFFChunk(&body, "ANIM", anim)
+
+	for _, frame := range frames {
+		bitstream, err := webpBitstream(frame)
+		if err != nil {
+			return err
+		}
+		anmf := appendUint24(nil, 0) // x offset / 2
+		anmf = appendUint24(anmf, 0) // y offset / 2
+		anmf = appendUint24(anmf, size.X-1)
+		anmf = appendUint24(anmf, size.Y-1)
+		anmf = appendUint24(anmf, int(min(a.Delay.Milliseconds(), 0xffffff)))
+		// frame replaces canvas without alpha blending, no disposal
+		anmf = append(anmf, 0x02)
+		writeRIFFChunk(&body, "ANMF", append(anmf, bitstream...))
+	}
+
+	header := binary.LittleEndian.AppendUint32([]byte("RIFF"), uint32(body.Len()))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := body.WriteTo(w)
+	return err
+}
+
+// webpBitstream encodes frame and returns its image chunks without RIFF header
+func webpBitstream(frame *image.RGBA) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := nativewebp.Encode(&buf, frame, nil); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errWebPFrame
+	}
+
+	var bitstream []byte
+	for pos := 12; pos+8 <= len(data); {
+		chunkLen := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := min(pos+8+chunkLen+chunkLen%2, len(data))
+		switch string(data[pos : pos+4]) {
+		case "ALPH", "VP8 ", "VP8L":
+			bitstream = append(bitstream, data[pos:end]...)
+		}
+		pos = end
+	}
+	if len(bitstream) == 0 {
+		return nil, errWebPFrame
+	}
+	return bitstream, nil
+}
+
+// writeRIFFChunk writes chunk with little endian size, odd sized data is padded
+func writeRIFFChunk(buf *bytes.Buffer, fourCC string, data []byte) {
+	buf.WriteString(fourCC)
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(data))))
+	buf.Write(data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+// appendUint24 appends 24-bit little endian value used in WebP headers
+func appendUint24(b []byte, v int) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16))
+}
@@ -0,0 +1,152 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrEmptyCommand is returned for command line without program
+	ErrEmptyCommand = errors.New("empty command")
+	// ErrUnterminatedQuote is returned for command line with unclosed quote or trailing backslash
+	ErrUnterminatedQuote = errors.New("unterminated quote in command")
+)
+
+const (
+	// maxOutput limits captured output quoted in errors
+	maxOutput = 500
+	waitDelay = time.Second
+)
+
+// Template is a command line with {placeholder} arguments. It is run without shell,
+// so file paths filled into arguments need no quoting
+type Template struct {
+	Args []string
+}
+
+// Parse splits command line into program and arguments like POSIX shell does without
+// expansions: whitespace separates arguments unless quoted, single quotes keep text as is,
+// inside double quotes backslash escapes only " and \, outside of quotes it escapes any character
+func Parse(s string) (Template, error) {
+	var args []string
+	var arg strings.Builder
+	// inArg is set once an argument started, so quoted empty strings are kept
+	inArg := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+			continue
+		case '\\':
+			i++
+			if i == len(s) {
+				return Template{}, ErrUnterminatedQuote
+			}
+			arg.WriteByte(s[i])
+		case '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return Template{}, ErrUnterminatedQuote
+			}
+			arg.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case '"':
+			closed := false
+			for i++; i < len(s); i++ {
+				if s[i] == '"' {
+					closed = true
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					i++
+				}
+				arg.WriteByte(s[i])
+			}
+			if !closed {
+				return Template{}, ErrUnterminatedQuote
+			}
+		default:
+			arg.WriteByte(c)
+		}
+		inArg = true
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return Template{}, ErrEmptyCommand
+	}
+	return Template{Args: args}, nil
+}
+
+// Fill returns command arguments with placeholders replaced by values
+func (t Template) Fill(values map[string]string) []string {
+	pairs := make([]string, 0, len(values)*2)
+	for placeholder, value := range values {
+		pairs = append(pairs, placeholder, value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = replacer.Replace(arg)
+	}
+	return args
+}
+
+// Result holds captured output of a finished command
+type Result struct {
+	Stdout []byte
+	Stderr []byte
+}
+
+// Run fills placeholders and runs the command, timeout of 0 means no limit.
+// Failed command error includes the tail of its stderr
+func (t Template) Run(ctx context.Context, values map[string]string, timeout time.Duration) (Result, error) {
+	if len(t.Args) == 0 {
+		return Result{}, ErrEmptyCommand
+	}
+	args := t.Fill(values)
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	// children of killed command may keep output pipes open
+	cmd.WaitDelay = waitDelay
+
+	err := cmd.Run()
+	result := Result{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}
+	if ctx.Err() == context.DeadlineExceeded {
+		return result, fmt.Errorf("%s timed out after %s", args[0], timeout)
+	}
+	if err != nil {
+		if output := tail(result.Stderr); output != "" {
+			return result, fmt.Errorf("%s: %w: %s", args[0], err, output)
+		}
+		return result, fmt.Errorf("%s: %w", args[0], err)
+	}
+	return result, nil
+}
+
+// tail returns the last part of command output as a single line
+func tail(output []byte) string {
+	s := strings.Join(strings.Fields(string(output)), " ")
+	if len(s) > maxOutput {
+		s = "..." + s[len(s)-maxOutput:]
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type parseTestCase struct {
+	comment     string
+	inputValue  string
+	expectedVal []string
+	expectError error
+}
+
+var ParseTestCase = []parseTestCase{
+	{
+		comment:     "Arguments separated by whitespace",
+		inputValue:  " tesseract\t{image}  {output} txt ",
+		expectedVal: []string{"tesseract", "{image}", "{output}", "txt"},
+	},
+	{
+		comment:     "Single quoted script",
+		inputValue:  `sh -c 'exit 3' {path}`,
+		expectedVal: []string{"sh", "-c", "exit 3", "{path}"},
+	},
+	{
+		comment:     "Double quotes with escaped quote and backslash",
+		inputValue:  `echo "say \"hi\" \\ \n"`,
+		expectedVal: []string{"echo", `say "hi" \ \n`},
+	},
+	{
+		comment:     "Backslash escapes space outside of quotes",
+		inputValue:  `cp {path} /mnt/my\ disk`,
+		expectedVal: []string{"cp", "{path}", "/mnt/my disk"},
+	},
+	{
+		comment:     "Quoted parts are joined into one argument",
+		inputValue:  `tool --name='a b'"c d"e`,
+		expectedVal: []string{"tool", "--name=a bc de"},
+	},
+	{
+		comment:     "Empty quoted argument is kept",
+		inputValue:  `tool '' ""`,
+		expectedVal: []string{"tool", "", ""},
+	},
+	{
+		comment:     "Unterminated single quote",
+		inputValue:  `sh -c 'exit 3 {path}`,
+		expectError: ErrUnterminatedQuote,
+	},
+	{
+		comment:     "Unterminated double quote",
+		inputValue:  `sh -c "exit \"3 {path}`,
+		expectError: ErrUnterminatedQuote,
+	},
+	{
+		comment:     "Trailing backslash",
+		inputValue:  `tool {path} \`,
+		expectError: ErrUnterminatedQuote,
+	},
+	{
+		comment:     "Only whitespace",
+		inputValue:  " \t ",
+		expectError: ErrEmptyCommand,
+	},
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range ParseTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got, err := Parse(tc.inputValue)
+			if !errors.Is(err, tc.expectError) {
+				t.Fatalf("%s test. want error: %v, got: %v", tc.comment, tc.expectError, err)
+			}
+			if fmt.Sprintf("%q", got.Args) != fmt.Sprintf("%q", tc.expectedVal) {
+				t.Errorf("%s test. want: %q, got: %q", tc.comment, tc.expectedVal, got.Args)
+			}
+		})
+	}
+}
+
+type fillTestCase struct {
+	comment     string
+	args        []string
+	values      map[string]string
+	expectedVal []string
+}
+
+var FillTestCase = []fillTestCase{
+	{
+		comment:     "Placeholders as whole arguments",
+		args:        []string{"tesseract", "{image}", "{output}"},
+		values:      map[string]string{"{image}": "/out/page 1.png", "{output}": "/out/page 1"},
+		expectedVal: []string{"tesseract", "/out/page 1.png", "/out/page 1"},
+	},
+	{
+		comment:     "Placeholders inside arguments",
+		args:        []string{"upload", "--name=p{page}", "{path}:{page}"},
+		values:      map[string]string{"{path}": "a.png", "{page}": "7"},
+		expectedVal: []string{"upload", "--name=p7", "a.png:7"},
+	},
+	{
+		comment:     "Values are not filled again",
+		args:        []string{"{path}{page}"},
+		values:      map[string]string{"{path}": "{page}", "{page}": "7"},
+		expectedVal: []string{"{page}7"},
+	},
+	{
+		comment:     "Unknown placeholders are kept",
+		args:        []string{"echo", "{dir}"},
+		values:      map[string]string{"{path}": "a.png"},
+		expectedVal: []string{"echo", "{dir}"},
+	},
+}
+
+func TestFill(t *testing.T) {
+	for _, tc := range FillTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			template := Template{Args: tc.args}
+			got := template.Fill(tc.values)
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tc.expectedVal) {
+				t.Errorf("%s test. want: %q, got: %q", tc.comment, tc.expectedVal, got)
+			}
+			if fmt.Sprintf("%q", template.Args) != fmt.Sprintf("%q", tc.args) {
+				t.Errorf("%s test. template is changed: %q", tc.comment, template.Args)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	template := Template{Args: []string{"sh", "-c", `echo "$1"; echo warning >&2`, "sh", "{path}"}}
+	result, err := template.Run(context.Background(), map[string]string{"{path}": "page 1.png"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Stdout) != "page 1.png\n" || string(result.Stderr) != "warning\n" {
+		t.Errorf("run test. want: page 1.png and warning, got: %q and %q", result.Stdout, result.Stderr)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	template := Template{Args: []string{"sh", "-c", "echo first >&2; echo last line >&2; exit 3"}}
+	_, err := template.Run(context.Background(), nil, 0)
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") || !strings.HasSuffix(err.Error(), "first last line") {
+		t.Errorf("failure test. want exit status with stderr tail, got: %v", err)
+	}
+
+	_, err = Template{Args: []string{"sh", "-c", "exit 1"}}.Run(context.Background(), nil, 0)
+	if err == nil || strings.HasSuffix(err.Error(), ": ") {
+		t.Errorf("failure test. want error without empty stderr, got: %v", err)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	// background child keeps output pipes open after the shell is killed
+	template := Template{Args: []string{"sh", "-c", "sleep 30 & sleep 30"}}
+	start := time.Now()
+	_, err := template.Run(context.Background(), nil, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("timeout test. want timeout error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond+waitDelay+time.Second {
+		t.Errorf("timeout test. command is not killed in time, took: %s", elapsed)
+	}
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Hook runs a command template from several goroutines, number of simultaneously
+// running commands is limited and output of every run is appended to a log
+type Hook struct {
+	Template Template
+	Timeout  time.Duration
+	slots    chan struct{}
+	log      *hookLog
+}
+
+// hookLog serializes writes of concurrent hooks
+type hookLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewHook creates hook running at most concurrency commands at once, nil log discards output
+func NewHook(template Template, timeout time.Duration, concurrency int, log io.Writer) *Hook {
+	if log == nil {
+		log = io.Discard
+	}
+	return &Hook{
+		Template: template,
+		Timeout:  timeout,
+		slots:    make(chan struct{}, max(concurrency, 1)),
+		log:      &hookLog{w: log},
+	}
+}
+
+// Run waits for a free slot and runs the command with filled placeholders
+func (h *Hook) Run(ctx context.Context, values map[string]string) error {
+	select {
+	case h.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	result, err := h.Template.Run(ctx, values, h.Timeout)
+	<-h.slots
+
+	status := "ok"
+	if err != nil {
+		status = err.Error()
+	}
+	h.log.write(h.Template.Fill(values), status, result)
+	return err
+}
+
+// write appends command line, its status and captured output to the log
+func (l *hookLog) write(args []string, status string, result Result) {
+	var b strings.Builder
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quote(arg)
+	}
+	fmt.Fprintf(&b, "$ %s\n# %s\n", strings.Join(quoted, " "), status)
+	for _, output := range [][]byte{result.Stdout, result.Stderr} {
+		if len(output) > 0 {
+			b.Write(output)
+			if output[len(output)-1] != '\n' {
+				b.WriteByte('\n')
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// hook output is informational, failed log write must not fail the hook
+	_, _ = io.WriteString(l.w, b.String())
+}
+
+// quote wraps argument in single quotes when Parse would split or unescape it
+func quote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\r'\"\\") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
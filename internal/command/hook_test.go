@@ -0,0 +1,60 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHookLog(t *testing.T) {
+	var log bytes.Buffer
+	hook := NewHook(Template{Args: []string{"sh", "-c", `echo "$1"; exit $2`, "sh", "{path}", "{page}"}}, 0, 1, &log)
+
+	if err := hook.Run(context.Background(), map[string]string{"{path}": "a.png", "{page}": "0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := hook.Run(context.Background(), map[string]string{"{path}": "b.png", "{page}": "2"}); err == nil {
+		t.Error("hook log test. want error of failed command")
+	}
+
+	want := "$ sh -c 'echo \"$1\"; exit $2' sh a.png 0\n# ok\na.png\n" +
+		"$ sh -c 'echo \"$1\"; exit $2' sh b.png 2\n# sh: exit status 2\nb.png\n"
+	if log.String() != want {
+		t.Errorf("hook log test. want: %q, got: %q", want, log.String())
+	}
+}
+
+func TestHookConcurrency(t *testing.T) {
+	const limit = 2
+	dir := t.TempDir()
+	var log bytes.Buffer
+	// every command prints number of commands running together with it
+	script := `touch "$1/$$"; ls "$1" | wc -l; sleep 0.3; rm "$1/$$"`
+	hook := NewHook(Template{Args: []string{"sh", "-c", script, "sh", "{dir}"}}, 0, limit, &log)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3*limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := hook.Run(context.Background(), map[string]string{"{dir}": dir}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	runs, running := 0, 0
+	for _, line := range strings.Split(log.String(), "\n") {
+		if n, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
+			runs++
+			running = max(running, n)
+		}
+	}
+	if runs != 3*limit || running != limit {
+		t.Errorf("concurrency test. want: %d runs with %d at once, got: %d runs with %d at once", 3*limit, limit, runs, running)
+	}
+}
@@ -0,0 +1,74 @@
+package compare
+
+import (
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Pair matches a page of the old document with a page of the new one by zero-based numbers,
+// -1 marks page missing in one of the documents
+type Pair struct {
+	A int
+	B int
+}
+
+// Align matches pages of two documents by their perceptual hashes. Longest sequence of
+// similar pages (hashes within distance bits) anchors the alignment, pages between anchors
+// are paired in order as changed, the rest of them are removed or added pages
+func Align(a, b []uint64, distance int) []Pair {
+	similar := func(i, j int) bool {
+		return imageutils.Hamming(a[i], b[j]) <= distance
+	}
+
+	// lcs[i][j] is length of the longest common sequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if similar(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var pairs []Pair
+	startA, startB := 0, 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case similar(i, j) && lcs[i][j] == lcs[i+1][j+1]+1:
+			pairs = append(pairs, gap(startA, i, startB, j)...)
+			pairs = append(pairs, Pair{A: i, B: j})
+			i, j = i+1, j+1
+			startA, startB = i, j
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return append(pairs, gap(startA, len(a), startB, len(b))...)
+}
+
+// Sequential matches pages by their numbers
+func Sequential(countA, countB int) []Pair {
+	return gap(0, countA, 0, countB)
+}
+
+// gap pairs pages a[startA:endA] and b[startB:endB] in order, extra pages are left unpaired
+func gap(startA, endA, startB, endB int) []Pair {
+	var pairs []Pair
+	for i := 0; startA+i < endA || startB+i < endB; i++ {
+		pair := Pair{A: -1, B: -1}
+		if startA+i < endA {
+			pair.A = startA + i
+		}
+		if startB+i < endB {
+			pair.B = startB + i
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
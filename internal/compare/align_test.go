@@ -0,0 +1,134 @@
+package compare
+
+import (
+	"testing"
+)
+
+// hashes of distinct pages, every two of them differ in at least 32 bits
+const (
+	hashA uint64 = 0
+	hashB uint64 = 0xffffffffffffffff
+	hashC uint64 = 0x00000000ffffffff
+	hashX uint64 = 0xffffffff00000000
+	hashY uint64 = 0x0f0f0f0f0f0f0f0f
+)
+
+type alignTestCase struct {
+	comment     string
+	a           []uint64
+	b           []uint64
+	expectedVal []Pair
+}
+
+var AlignTestCase = []alignTestCase{
+	{
+		comment:     "Same pages",
+		a:           []uint64{hashA, hashB, hashC},
+		b:           []uint64{hashA, hashB, hashC},
+		expectedVal: []Pair{{0, 0}, {1, 1}, {2, 2}},
+	},
+	{
+		comment:     "Page inserted in the middle",
+		a:           []uint64{hashA, hashB, hashC},
+		b:           []uint64{hashA, hashX, hashB, hashC},
+		expectedVal: []Pair{{0, 0}, {-1, 1}, {1, 2}, {2, 3}},
+	},
+	{
+		comment:     "Page removed from the middle",
+		a:           []uint64{hashA, hashB, hashC},
+		b:           []uint64{hashA, hashC},
+		expectedVal: []Pair{{0, 0}, {1, -1}, {2, 1}},
+	},
+	{
+		comment:     "Slightly changed page within distance",
+		a:           []uint64{hashA, hashB, hashC},
+		b:           []uint64{hashA, hashB ^ 0b11, hashC},
+		expectedVal: []Pair{{0, 0}, {1, 1}, {2, 2}},
+	},
+	{
+		comment:     "Changed page between anchors is paired",
+		a:           []uint64{hashA, hashB, hashC},
+		b:           []uint64{hashA, hashY, hashC},
+		expectedVal: []Pair{{0, 0}, {1, 1}, {2, 2}},
+	},
+	{
+		comment:     "Pages shifted by inserted title page",
+		a:           []uint64{hashB, hashC, hashX},
+		b:           []uint64{hashY, hashB, hashC, hashX},
+		expectedVal: []Pair{{-1, 0}, {0, 1}, {1, 2}, {2, 3}},
+	},
+	{
+		comment:     "Pages added at the end",
+		a:           []uint64{hashA},
+		b:           []uint64{hashA, hashX, hashY},
+		expectedVal: []Pair{{0, 0}, {-1, 1}, {-1, 2}},
+	},
+	{
+		comment:     "Empty old document",
+		a:           nil,
+		b:           []uint64{hashA},
+		expectedVal: []Pair{{-1, 0}},
+	},
+}
+
+func TestAlign(t *testing.T) {
+	for _, tc := range AlignTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := Align(tc.a, tc.b, 2)
+			if !equalPairs(got, tc.expectedVal) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+type sequentialTestCase struct {
+	comment     string
+	countA      int
+	countB      int
+	expectedVal []Pair
+}
+
+var SequentialTestCase = []sequentialTestCase{
+	{
+		comment:     "Same length",
+		countA:      2,
+		countB:      2,
+		expectedVal: []Pair{{0, 0}, {1, 1}},
+	},
+	{
+		comment:     "New document is longer",
+		countA:      1,
+		countB:      3,
+		expectedVal: []Pair{{0, 0}, {-1, 1}, {-1, 2}},
+	},
+	{
+		comment:     "Old document is longer",
+		countA:      2,
+		countB:      1,
+		expectedVal: []Pair{{0, 0}, {1, -1}},
+	},
+}
+
+func TestSequential(t *testing.T) {
+	for _, tc := range SequentialTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := Sequential(tc.countA, tc.countB)
+			if !equalPairs(got, tc.expectedVal) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+func equalPairs(a, b []Pair) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+
+	"github.com/gen2brain/go-fitz"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// SummaryName is the name of comparison summary saved into output folder
+const SummaryName = "diff.json"
+
+// page statuses
+const (
+	Unchanged = "unchanged"
+	Changed   = "changed"
+	Added     = "added"
+	Removed   = "removed"
+)
+
+// Differ renders pages of two documents with the same settings and compares them
+type Differ struct {
+	DocA *fitz.Document
+	DocB *fitz.Document
+	DPI  float64
+	// Threshold is channel difference (0-255) which is still treated as the same color
+	Threshold int
+	Highlight color.RGBA
+	ImgType   string
+	SavePath  string
+}
+
+// Result describes comparison of a page pair, page numbers are one-based
+type Result struct {
+	Status string `json:"status"`
+	A      int    `json:"a,omitempty"`
+	B      int    `json:"b,omitempty"`
+	// ChangedPercent is share of changed pixels
+	ChangedPercent float64  `json:"changedPercent,omitempty"`
+	Files          []string `json:"files,omitempty"`
+}
+
+// Compare renders both pages of the pair, for changed pages highlighted image
+// and difference mask are saved under the number of the new page
+func (d *Differ) Compare(pair Pair) (Result, error) {
+	switch {
+	case pair.A < 0:
+		return Result{Status: Added, B: pair.B + 1}, nil
+	case pair.B < 0:
+		return Result{Status: Removed, A: pair.A + 1}, nil
+	}
+
+	result := Result{Status: Unchanged, A: pair.A + 1, B: pair.B + 1}
+	imgA, err := d.DocA.ImageDPI(pair.A, d.DPI)
+	if err != nil {
+		return result, err
+	}
+	imgB, err := d.DocB.ImageDPI(pair.B, d.DPI)
+	if err != nil {
+		return result, err
+	}
+	return d.compareImages(result, imgA, imgB)
+}
+
+// compareImages fills result of a rendered page pair and saves images of their difference
+func (d *Differ) compareImages(result Result, imgA, imgB *image.RGBA) (Result, error) {
+	mask, changed := imageutils.DiffMask(imgA, imgB, d.Threshold)
+	if changed == 0 {
+		return result, nil
+	}
+	result.Status = Changed
+	result.ChangedPercent = float64(changed) * 100 / float64(mask.Rect.Dx()*mask.Rect.Dy())
+
+	diffFName := fmt.Sprintf("page%03d_diff.%s", result.B, d.ImgType)
+	if err := imageutils.Save(filepath.Join(d.SavePath, diffFName), d.ImgType,
+		imageutils.Highlight(imgB, mask, d.Highlight)); err != nil {
+		return result, err
+	}
+	maskFName := fmt.Sprintf("page%03d_mask.png", result.B)
+	if err := imageutils.Save(filepath.Join(d.SavePath, maskFName), "png", mask); err != nil {
+		return result, err
+	}
+	result.Files = []string{diffFName, maskFName}
+	return result, nil
+}
+
+// Summary lists comparison results in page order with page numbers grouped by status
+type Summary struct {
+	A       string   `json:"a"`
+	B       string   `json:"b"`
+	Changed []int    `json:"changed"`
+	Added   []int    `json:"added"`
+	Removed []int    `json:"removed"`
+	Pages   []Result `json:"pages"`
+}
+
+// NewSummary groups results, changed and added pages are numbered as in the new document,
+// removed pages as in the old one
+func NewSummary(a, b string, results []Result) Summary {
+	s := Summary{A: a, B: b, Changed: []int{}, Added: []int{}, Removed: []int{}, Pages: results}
+	for _, r := range results {
+		switch r.Status {
+		case Changed:
+			s.Changed = append(s.Changed, r.B)
+		case Added:
+			s.Added = append(s.Added, r.B)
+		case Removed:
+			s.Removed = append(s.Removed, r.A)
+		}
+	}
+	return s
+}
+
+// Save writes summary as JSON
+func (s Summary) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,136 @@
+package compare
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type compareTestCase struct {
+	comment  string
+	sizeB    image.Point
+	changeB  map[image.Point]color.RGBA
+	expected Result
+	// highlighted lists points painted with highlight color on the diff image
+	highlighted []image.Point
+}
+
+var gray = color.RGBA{R: 128, G: 128, B: 128, A: 255}
+
+var CompareTestCase = []compareTestCase{
+	{
+		comment:  "Same pages",
+		sizeB:    image.Pt(10, 10),
+		expected: Result{Status: Unchanged, A: 1, B: 2},
+	},
+	{
+		comment:  "Difference within threshold",
+		sizeB:    image.Pt(10, 10),
+		changeB:  map[image.Point]color.RGBA{{3, 3}: {R: 120, G: 130, B: 128, A: 255}},
+		expected: Result{Status: Unchanged, A: 1, B: 2},
+	},
+	{
+		comment: "Single changed pixel",
+		sizeB:   image.Pt(10, 10),
+		changeB: map[image.Point]color.RGBA{{3, 4}: {A: 255}},
+		expected: Result{Status: Changed, A: 1, B: 2, ChangedPercent: 1,
+			Files: []string{"page002_diff.png", "page002_mask.png"}},
+		highlighted: []image.Point{{3, 4}},
+	},
+	{
+		comment: "Taller new page",
+		sizeB:   image.Pt(10, 12),
+		expected: Result{Status: Changed, A: 1, B: 2, ChangedPercent: 20 * 100.0 / 120,
+			Files: []string{"page002_diff.png", "page002_mask.png"}},
+		highlighted: []image.Point{{0, 10}, {9, 11}},
+	},
+}
+
+func TestCompareImages(t *testing.T) {
+	highlight := color.RGBA{R: 255, A: 255}
+	for _, tc := range CompareTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			d := &Differ{Threshold: 10, Highlight: highlight, ImgType: "png", SavePath: t.TempDir()}
+			imgA := filled(image.Pt(10, 10), gray)
+			imgB := filled(tc.sizeB, gray)
+			for p, c := range tc.changeB {
+				imgB.SetRGBA(p.X, p.Y, c)
+			}
+
+			got, err := d.compareImages(Result{Status: Unchanged, A: 1, B: 2}, imgA, imgB)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tc.expected.Status || got.A != tc.expected.A || got.B != tc.expected.B ||
+				math.Abs(got.ChangedPercent-tc.expected.ChangedPercent) > 1e-9 ||
+				len(got.Files) != len(tc.expected.Files) {
+				t.Fatalf("%s test. want: %+v, got: %+v", tc.comment, tc.expected, got)
+			}
+			for i, fname := range tc.expected.Files {
+				if got.Files[i] != fname {
+					t.Errorf("%s test. want file: %s, got: %s", tc.comment, fname, got.Files[i])
+				}
+			}
+			if len(tc.highlighted) == 0 {
+				return
+			}
+
+			diff := loadPNG(t, filepath.Join(d.SavePath, got.Files[0]))
+			mask := loadPNG(t, filepath.Join(d.SavePath, got.Files[1]))
+			for _, p := range tc.highlighted {
+				if c := color.RGBAModel.Convert(diff.At(p.X, p.Y)); c != highlight {
+					t.Errorf("%s test. at %v want highlight, got: %v", tc.comment, p, c)
+				}
+				if c := color.GrayModel.Convert(mask.At(p.X, p.Y)).(color.Gray); c.Y != 255 {
+					t.Errorf("%s test. at %v want changed mask pixel, got: %v", tc.comment, p, c)
+				}
+			}
+			// unchanged pixels are faded towards white
+			if c := color.RGBAModel.Convert(diff.At(0, 0)).(color.RGBA); c.R <= gray.R || c.R == 255 {
+				t.Errorf("%s test. want faded unchanged pixel, got: %v", tc.comment, c)
+			}
+		})
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	results := []Result{
+		{Status: Unchanged, A: 1, B: 1},
+		{Status: Added, B: 2},
+		{Status: Changed, A: 2, B: 3},
+		{Status: Removed, A: 3},
+	}
+	s := NewSummary("a.pdf", "b.pdf", results)
+	if len(s.Changed) != 1 || s.Changed[0] != 3 || len(s.Added) != 1 || s.Added[0] != 2 ||
+		len(s.Removed) != 1 || s.Removed[0] != 3 || len(s.Pages) != len(results) {
+		t.Errorf("summary test. got: %+v", s)
+	}
+}
+
+func filled(size image.Point, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func loadPNG(t *testing.T, path string) image.Image {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
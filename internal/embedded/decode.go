@@ -0,0 +1,272 @@
+package embedded
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
+)
+
+// colorSpace tells how samples of an image map to colors
+type colorSpace struct {
+	// n is number of components of a sample
+	n int
+	// palette of indexed color spaces
+	palette color.Palette
+}
+
+// encodePNG decodes image stream into png, stencil masks are drawn black on white
+// when stencil is true and white where painted otherwise
+func encodePNG(r *pdf.Reader, stm pdf.Stream, stencil bool) ([]byte, error) {
+	data, filter, err := r.Decode(stm)
+	if err != nil {
+		return nil, err
+	}
+	var img image.Image
+	switch filter {
+	case "":
+		img, err = samples(r, stm.Dict, data, stencil)
+	case "DCTDecode":
+		// soft masks may be JPEG compressed
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnsupported, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// samples converts decoded image data into an image. Image masks become gray images
+// white where they are painted, or black on white when stencil is true
+func samples(r *pdf.Reader, dict pdf.Dict, data []byte, stencil bool) (image.Image, error) {
+	width, _ := r.Resolve(dict["Width"]).(int)
+	height, _ := r.Resolve(dict["Height"]).(int)
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("%w: size %dx%d", ErrUnsupported, width, height)
+	}
+	imageMask, _ := r.Resolve(dict["ImageMask"]).(bool)
+	bpc, ok := r.Resolve(dict["BitsPerComponent"]).(int)
+	if imageMask || !ok {
+		bpc = 1
+	}
+	switch bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		return nil, fmt.Errorf("%w: %d bits per component", ErrUnsupported, bpc)
+	}
+	cs := colorSpace{n: 1}
+	if !imageMask {
+		var err error
+		if cs, err = colorSpaceOf(r, dict["ColorSpace"], 0); err != nil {
+			return nil, err
+		}
+	}
+	decode := decodeRanges(r, dict["Decode"], cs, bpc, imageMask && !stencil)
+
+	// rows start at byte boundary, truncated data is padded
+	stride := (width*cs.n*bpc + 7) / 8
+	if len(data) < stride*height {
+		data = append(data, make([]byte, stride*height-len(data))...)
+	}
+	rect := image.Rect(0, 0, width, height)
+	maxValue := float64(int(1)<<bpc - 1)
+	var v [4]float64
+	// pixel decodes components of pixel x of a row into v
+	pixel := func(row []byte, x int) {
+		for k := 0; k < cs.n; k++ {
+			s := float64(sample(row, x*cs.n+k, bpc))
+			v[k] = decode[2*k] + s*(decode[2*k+1]-decode[2*k])/maxValue
+		}
+	}
+
+	switch {
+	case cs.palette != nil:
+		img := image.NewPaletted(rect, cs.palette)
+		for y := 0; y < height; y++ {
+			row := data[y*stride:]
+			for x := 0; x < width; x++ {
+				pixel(row, x)
+				img.Pix[y*img.Stride+x] = uint8(min(max(int(v[0]+0.5), 0), len(cs.palette)-1))
+			}
+		}
+		return img, nil
+	case cs.n == 1 && bpc == 16:
+		img := image.NewGray16(rect)
+		for y := 0; y < height; y++ {
+			row := data[y*stride:]
+			for x := 0; x < width; x++ {
+				pixel(row, x)
+				img.SetGray16(x, y, color.Gray16{Y: uint16(unit(v[0])*65535 + 0.5)})
+			}
+		}
+		return img, nil
+	case cs.n == 1:
+		img := image.NewGray(rect)
+		for y := 0; y < height; y++ {
+			row := data[y*stride:]
+			for x := 0; x < width; x++ {
+				pixel(row, x)
+				img.Pix[y*img.Stride+x] = uint8(unit(v[0])*255 + 0.5)
+			}
+		}
+		return img, nil
+	case cs.n == 3 && bpc == 16:
+		img := image.NewRGBA64(rect)
+		for y := 0; y < height; y++ {
+			row := data[y*stride:]
+			for x := 0; x < width; x++ {
+				pixel(row, x)
+				img.SetRGBA64(x, y, color.RGBA64{
+					R: uint16(unit(v[0])*65535 + 0.5), G: uint16(unit(v[1])*65535 + 0.5), B: uint16(unit(v[2])*65535 + 0.5), A: 0xffff,
+				})
+			}
+		}
+		return img, nil
+	}
+	img := image.NewRGBA(rect)
+	for y := 0; y < height; y++ {
+		row := data[y*stride:]
+		for x := 0; x < width; x++ {
+			pixel(row, x)
+			img.SetRGBA(x, y, cs.rgba(v[:cs.n]))
+		}
+	}
+	return img, nil
+}
+
+// sample returns component i of a row
+func sample(row []byte, i, bpc int) int {
+	switch bpc {
+	case 8:
+		return int(row[i])
+	case 16:
+		return int(row[2*i])<<8 | int(row[2*i+1])
+	}
+	bit := i * bpc
+	return int(row[bit/8]>>(8-bpc-bit%8)) & (1<<bpc - 1)
+}
+
+// decodeRanges returns Decode array of an image or its default: 0 to 1 for every component,
+// palette indexes for indexed images. Ranges are reversed when invert is true
+func decodeRanges(r *pdf.Reader, obj pdf.Object, cs colorSpace, bpc int, invert bool) []float64 {
+	ranges := make([]float64, 0, 2*cs.n)
+	arr, _ := r.Resolve(obj).(pdf.Array)
+	for _, item := range arr {
+		switch n := r.Resolve(item).(type) {
+		case int:
+			ranges = append(ranges, float64(n))
+		case float64:
+			ranges = append(ranges, n)
+		}
+	}
+	if len(ranges) != 2*cs.n {
+		ranges = ranges[:0]
+		for k := 0; k < cs.n; k++ {
+			if cs.palette != nil {
+				ranges = append(ranges, 0, float64(int(1)<<bpc-1))
+			} else {
+				ranges = append(ranges, 0, 1)
+			}
+		}
+	}
+	if invert {
+		for k := 0; k < len(ranges); k += 2 {
+			ranges[k], ranges[k+1] = ranges[k+1], ranges[k]
+		}
+	}
+	return ranges
+}
+
+// colorSpaceOf reads color space of an image, depth limits nested color spaces
+func colorSpaceOf(r *pdf.Reader, obj pdf.Object, depth int) (colorSpace, error) {
+	obj = r.Resolve(obj)
+	name, _ := obj.(pdf.Name)
+	arr, _ := obj.(pdf.Array)
+	if len(arr) > 0 {
+		name, _ = r.Resolve(arr[0]).(pdf.Name)
+	}
+
+	switch name {
+	case "DeviceGray", "CalGray":
+		return colorSpace{n: 1}, nil
+	case "DeviceRGB", "CalRGB":
+		return colorSpace{n: 3}, nil
+	case "DeviceCMYK":
+		return colorSpace{n: 4}, nil
+	case "ICCBased":
+		if len(arr) < 2 {
+			break
+		}
+		if stm, ok := r.Resolve(arr[1]).(pdf.Stream); ok {
+			switch n, _ := r.Resolve(stm.Dict["N"]).(int); n {
+			case 1, 3, 4:
+				return colorSpace{n: n}, nil
+			}
+			if alt, ok := stm.Dict["Alternate"]; ok && depth < 2 {
+				return colorSpaceOf(r, alt, depth+1)
+			}
+		}
+	case "Indexed":
+		if len(arr) < 4 || depth > 0 {
+			break
+		}
+		base, err := colorSpaceOf(r, arr[1], depth+1)
+		if err != nil {
+			return colorSpace{}, err
+		}
+		hival, _ := r.Resolve(arr[2]).(int)
+		var lookup []byte
+		switch l := r.Resolve(arr[3]).(type) {
+		case pdf.String:
+			lookup = []byte(l)
+		case pdf.Stream:
+			var filter pdf.Name
+			if lookup, filter, err = r.Decode(l); err == nil && filter != "" {
+				err = fmt.Errorf("%w: palette filter %s", ErrUnsupported, filter)
+			}
+			if err != nil {
+				return colorSpace{}, err
+			}
+		}
+		palette := make(color.Palette, min(max(hival, 0), 255)+1)
+		for i := range palette {
+			var v [4]float64
+			for k := 0; k < base.n; k++ {
+				if j := i*base.n + k; j < len(lookup) {
+					v[k] = float64(lookup[j]) / 255
+				}
+			}
+			palette[i] = base.rgba(v[:base.n])
+		}
+		return colorSpace{n: 1, palette: palette}, nil
+	}
+	return colorSpace{}, fmt.Errorf("%w: color space %v", ErrUnsupported, obj)
+}
+
+// rgba converts components from 0 to 1 into a color
+func (cs colorSpace) rgba(v []float64) color.RGBA {
+	switch len(v) {
+	case 1:
+		g := uint8(unit(v[0])*255 + 0.5)
+		return color.RGBA{g, g, g, 255}
+	case 3:
+		return color.RGBA{uint8(unit(v[0])*255 + 0.5), uint8(unit(v[1])*255 + 0.5), uint8(unit(v[2])*255 + 0.5), 255}
+	}
+	r, g, b := color.CMYKToRGB(uint8(unit(v[0])*255+0.5), uint8(unit(v[1])*255+0.5), uint8(unit(v[2])*255+0.5), uint8(unit(v[3])*255+0.5))
+	return color.RGBA{r, g, b, 255}
+}
+
+// unit clamps value to 0..1
+func unit(v float64) float64 {
+	return min(max(v, 0), 1)
+}
@@ -0,0 +1,209 @@
+package embedded
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
+)
+
+// CatalogName is the name of the list of extracted images saved into output folder
+const CatalogName = "images.json"
+
+// ErrUnsupported is returned for images whose encoding or color space can't be converted
+var ErrUnsupported = errors.New("unsupported image")
+
+// jp2Signature starts JPEG 2000 files, JPX streams without it are bare codestreams
+var jp2Signature = []byte("\x00\x00\x00\x0cjP  \r\n\x87\n")
+
+// Image is an image placed on a page
+type Image struct {
+	// Object is number of the image object, 0 for inline images
+	Object int
+	// Format is jpg or jp2 (j2k for bare codestreams) for streams copied as is, png otherwise
+	Format string
+	Width  int
+	Height int
+	// BBox is in points of the page as rendered with origin at the top left corner
+	BBox Box
+
+	r      *pdf.Reader
+	stream pdf.Stream
+}
+
+// Box is a rectangle in points
+type Box struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// PageImages returns images placed on a page in drawing order, images of forms included
+func PageImages(r *pdf.Reader, pageNum int) ([]Image, error) {
+	placed, err := r.Images(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	box, rotate, err := r.PageBox(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]Image, 0, len(placed))
+	for _, p := range placed {
+		dict := p.Stream.Dict
+		width, _ := r.Resolve(dict["Width"]).(int)
+		height, _ := r.Resolve(dict["Height"]).(int)
+		rect := p.Rect.Rendered(box, rotate)
+		images = append(images, Image{
+			Object: p.Ref.Num,
+			Format: format(r, p.Stream),
+			Width:  width,
+			Height: height,
+			BBox:   Box{X: toPt(rect.X0), Y: toPt(rect.Y0), Width: toPt(rect.X1 - rect.X0), Height: toPt(rect.Y1 - rect.Y0)},
+			r:      r,
+			stream: p.Stream,
+		})
+	}
+	return images, nil
+}
+
+// format tells file format of the image by its last filter
+func format(r *pdf.Reader, stm pdf.Stream) string {
+	filter := r.Resolve(stm.Dict["Filter"])
+	if filters, ok := filter.(pdf.Array); ok && len(filters) > 0 {
+		filter = r.Resolve(filters[len(filters)-1])
+	}
+	switch filter {
+	case pdf.Name("DCTDecode"):
+		return "jpg"
+	case pdf.Name("JPXDecode"):
+		// raw data is needed to tell container from codestream, filters before JPX are rare
+		if data, _, err := r.Decode(stm); err == nil && !bytes.HasPrefix(data, jp2Signature) {
+			return "j2k"
+		}
+		return "jp2"
+	}
+	return "png"
+}
+
+// toPt rounds points to hundredths
+func toPt(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Data returns content of the image file: JPEG and JPEG 2000 streams are copied byte
+// for byte, other images are decoded and encoded as png
+func (img Image) Data() ([]byte, error) {
+	if img.Format != "png" {
+		data, _, err := img.r.Decode(img.stream)
+		return data, err
+	}
+	// stencil masks are painted with fill color, they are saved as black on white
+	stencil, _ := img.r.Resolve(img.stream.Dict["ImageMask"]).(bool)
+	return encodePNG(img.r, img.stream, stencil)
+}
+
+// HasMask tells if the image has a soft mask or a stencil mask
+func (img Image) HasMask() bool {
+	_, ok := img.mask()
+	return ok
+}
+
+// Mask returns soft or stencil mask of the image as grayscale png where white is opaque
+func (img Image) Mask() ([]byte, error) {
+	stm, ok := img.mask()
+	if !ok {
+		return nil, fmt.Errorf("image has no mask")
+	}
+	return encodePNG(img.r, stm, false)
+}
+
+// mask returns SMask stream or Mask stream, color key masks given as arrays are not images
+func (img Image) mask() (pdf.Stream, bool) {
+	for _, key := range []pdf.Name{"SMask", "Mask"} {
+		if stm, ok := img.r.Resolve(img.stream.Dict[key]).(pdf.Stream); ok {
+			return stm, true
+		}
+	}
+	return pdf.Stream{}, false
+}
+
+// Catalog lists extracted images. Images are identified by PDF object, so an image placed
+// several times is saved once, named after its first placement. Inline images have no object
+// and every one of them is saved
+type Catalog struct {
+	Source string   `json:"source"`
+	Images []*Entry `json:"images"`
+
+	byObject map[int]*Entry
+}
+
+// Entry is a saved image with all its placements
+type Entry struct {
+	// Object is number of the image object, omitted for inline images
+	Object int    `json:"object,omitempty"`
+	File   string `json:"file"`
+	// Mask is file of the soft or stencil mask of the image
+	Mask       string      `json:"mask,omitempty"`
+	Format     string      `json:"format"`
+	Width      int         `json:"width"`
+	Height     int         `json:"height"`
+	Placements []Placement `json:"placements"`
+}
+
+// Placement is one-based page number and position of an image on it
+type Placement struct {
+	Page int `json:"page"`
+	BBox Box `json:"bbox"`
+}
+
+// Place records placement of an image on a page (zero-based number) when its object
+// is already in the catalog, false is returned for images which are not saved yet
+func (c *Catalog) Place(pageNum int, img Image) bool {
+	entry, ok := c.byObject[img.Object]
+	if !ok || img.Object == 0 {
+		return false
+	}
+	entry.Placements = append(entry.Placements, Placement{Page: pageNum + 1, BBox: img.BBox})
+	return true
+}
+
+// Add records an image saved as file with its mask file (empty without mask)
+// placed on a page (zero-based number)
+func (c *Catalog) Add(pageNum int, img Image, file, mask string) *Entry {
+	if c.byObject == nil {
+		c.byObject = make(map[int]*Entry)
+	}
+	entry := &Entry{
+		Object:     img.Object,
+		File:       file,
+		Mask:       mask,
+		Format:     img.Format,
+		Width:      img.Width,
+		Height:     img.Height,
+		Placements: []Placement{{Page: pageNum + 1, BBox: img.BBox}},
+	}
+	if img.Object != 0 {
+		c.byObject[img.Object] = entry
+	}
+	c.Images = append(c.Images, entry)
+	return entry
+}
+
+// Save writes catalog as JSON
+func (c *Catalog) Save(path string) error {
+	if c.Images == nil {
+		c.Images = []*Entry{}
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,351 @@
+package embedded
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
+)
+
+// document builds PDF with one 200x100 page drawing content, objects are numbered from 5
+// and listed in page resources as Im5, Im6...
+func document(t *testing.T, rotate int, content string, objects ...string) *pdf.Reader {
+	t.Helper()
+	xobjects := ""
+	for i := range objects {
+		xobjects += fmt.Sprintf("/Im%d %d 0 R ", i+5, i+5)
+	}
+	all := append([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 100] /Rotate %d /Contents 4 0 R "+
+			"/Resources << /XObject << %s>> >> >>", rotate, xobjects),
+		stream("", []byte(content)),
+	}, objects...)
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(all))
+	for i, obj := range all {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(all)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(all)+1, xref)
+
+	r, err := pdf.NewReader(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// stream makes stream object with entries of dict and data
+func stream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func jpegData(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pageImage returns the only image of the page
+func pageImage(t *testing.T, r *pdf.Reader) Image {
+	t.Helper()
+	images, err := PageImages(r, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 1 {
+		t.Fatalf("want one image, got: %d", len(images))
+	}
+	return images[0]
+}
+
+// pixels decodes png and lists its pixels row by row
+func pixels(t *testing.T, data []byte) []color.RGBA {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []color.RGBA
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			got = append(got, color.RGBAModel.Convert(img.At(x, y)).(color.RGBA))
+		}
+	}
+	return got
+}
+
+func TestJPEGPassThrough(t *testing.T) {
+	data := jpegData(t, 4, 2)
+	r := document(t, 0, "q 100 0 0 50 10 20 cm /Im5 Do Q",
+		stream("/Subtype /Image /Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode", data))
+	img := pageImage(t, r)
+	want := Image{Object: 5, Format: "jpg", Width: 4, Height: 2, BBox: Box{X: 10, Y: 30, Width: 100, Height: 50}}
+	if img.Object != want.Object || img.Format != want.Format || img.Width != want.Width ||
+		img.Height != want.Height || img.BBox != want.BBox {
+		t.Errorf("jpeg test. want: %+v, got: %+v", want, img)
+	}
+	got, err := img.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("jpeg test. want stream copied byte for byte")
+	}
+}
+
+type jpxTestCase struct {
+	comment     string
+	data        []byte
+	expectedVal string
+}
+
+var JPXTestCase = []jpxTestCase{
+	{comment: "JP2 file", data: append([]byte("\x00\x00\x00\x0cjP  \r\n\x87\n"), 1, 2, 3), expectedVal: "jp2"},
+	{comment: "Bare codestream", data: []byte("\xff\x4f\xff\x51\x00"), expectedVal: "j2k"},
+}
+
+func TestJPXPassThrough(t *testing.T) {
+	for _, tc := range JPXTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			r := document(t, 0, "/Im5 Do", stream("/Subtype /Image /Width 8 /Height 8 /Filter /JPXDecode", tc.data))
+			img := pageImage(t, r)
+			if img.Format != tc.expectedVal {
+				t.Errorf("%s test. want: %s, got: %s", tc.comment, tc.expectedVal, img.Format)
+			}
+			if got, err := img.Data(); err != nil || !bytes.Equal(got, tc.data) {
+				t.Errorf("%s test. want stream copied byte for byte, got: %v %v", tc.comment, got, err)
+			}
+		})
+	}
+}
+
+type bboxTestCase struct {
+	rotate      int
+	expectedVal Box
+}
+
+var BBoxTestCase = []bboxTestCase{
+	{rotate: 0, expectedVal: Box{X: 10, Y: 30, Width: 100, Height: 50}},
+	{rotate: 90, expectedVal: Box{X: 20, Y: 10, Width: 50, Height: 100}},
+	{rotate: 180, expectedVal: Box{X: 90, Y: 20, Width: 100, Height: 50}},
+	{rotate: 270, expectedVal: Box{X: 30, Y: 90, Width: 50, Height: 100}},
+}
+
+func TestBBoxRotation(t *testing.T) {
+	for _, tc := range BBoxTestCase {
+		t.Run(fmt.Sprint(tc.rotate), func(t *testing.T) {
+			r := document(t, tc.rotate, "100 0 0 50 10 20 cm /Im5 Do",
+				stream("/Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8", []byte{0}))
+			if got := pageImage(t, r).BBox; got != tc.expectedVal {
+				t.Errorf("rotation %d test. want: %+v, got: %+v", tc.rotate, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+var (
+	black  = color.RGBA{0, 0, 0, 255}
+	white  = color.RGBA{255, 255, 255, 255}
+	red    = color.RGBA{255, 0, 0, 255}
+	blue   = color.RGBA{0, 0, 255, 255}
+	gray   = color.RGBA{128, 128, 128, 255}
+	yellow = color.RGBA{255, 255, 0, 255}
+)
+
+type dataTestCase struct {
+	comment string
+	// objects start with the image drawn as Im5
+	objects     []string
+	expectedVal []color.RGBA
+}
+
+var DataTestCase = []dataTestCase{
+	{
+		comment: "Flate compressed RGB",
+		objects: []string{stream("/Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+			deflate([]byte{255, 0, 0, 0, 0, 255}))},
+		expectedVal: []color.RGBA{red, blue},
+	},
+	{
+		comment:     "Gray with 4 bits and inverted decode",
+		objects:     []string{stream("/Subtype /Image /Width 3 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 4 /Decode [1 0]", []byte{0x0f, 0x80})},
+		expectedVal: []color.RGBA{white, black, {119, 119, 119, 255}},
+	},
+	{
+		comment: "Indexed with one bit per pixel and rows padded to bytes",
+		objects: []string{stream("/Subtype /Image /Width 2 /Height 2 /ColorSpace [/Indexed /DeviceRGB 1 <ff0000ffff00>] /BitsPerComponent 1",
+			[]byte{0x40, 0x80})},
+		expectedVal: []color.RGBA{red, yellow, yellow, red},
+	},
+	{
+		comment: "ICC based CMYK",
+		objects: []string{stream("/Subtype /Image /Width 1 /Height 1 /ColorSpace [/ICCBased 6 0 R] /BitsPerComponent 8", []byte{0, 0, 255, 0}),
+			stream("/N 4", nil)},
+		expectedVal: []color.RGBA{yellow},
+	},
+	{
+		comment:     "Stencil mask drawn black on white",
+		objects:     []string{stream("/Subtype /Image /Width 2 /Height 1 /ImageMask true", []byte{0x40})},
+		expectedVal: []color.RGBA{black, white},
+	},
+	{
+		comment:     "16 bit gray",
+		objects:     []string{stream("/Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 16", []byte{0x80, 0x80})},
+		expectedVal: []color.RGBA{gray},
+	},
+}
+
+func TestData(t *testing.T) {
+	for _, tc := range DataTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			img := pageImage(t, document(t, 0, "/Im5 Do", tc.objects...))
+			if img.Format != "png" {
+				t.Fatalf("%s test. want: png, got: %s", tc.comment, img.Format)
+			}
+			data, err := img.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pixels(t, data); fmt.Sprint(got) != fmt.Sprint(tc.expectedVal) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+type maskTestCase struct {
+	comment     string
+	mask        string
+	expectedVal []color.RGBA
+}
+
+var MaskTestCase = []maskTestCase{
+	{
+		comment:     "Soft mask",
+		mask:        "/SMask 6 0 R",
+		expectedVal: []color.RGBA{black, white},
+	},
+	{
+		comment:     "Stencil mask is white where image is painted",
+		mask:        "/Mask 7 0 R",
+		expectedVal: []color.RGBA{white, black},
+	},
+	{
+		comment: "Color key mask is not an image",
+		mask:    "/Mask [0 10]",
+	},
+}
+
+func TestMask(t *testing.T) {
+	for _, tc := range MaskTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			r := document(t, 0, "/Im5 Do",
+				stream("/Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 "+tc.mask, make([]byte, 6)),
+				stream("/Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8", []byte{0, 255}),
+				stream("/Subtype /Image /Width 2 /Height 1 /ImageMask true", []byte{0x40}))
+			images, err := PageImages(r, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			img := images[0]
+			if img.HasMask() != (tc.expectedVal != nil) {
+				t.Fatalf("%s test. want mask: %v, got: %v", tc.comment, tc.expectedVal != nil, img.HasMask())
+			}
+			if tc.expectedVal == nil {
+				return
+			}
+			data, err := img.Mask()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pixels(t, data); fmt.Sprint(got) != fmt.Sprint(tc.expectedVal) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+func TestInlineImage(t *testing.T) {
+	r := document(t, 0, "q 20 0 0 10 0 90 cm BI /W 2 /H 1 /CS /RGB /BPC 8 /F /AHx ID ff00000000ff> EI Q")
+	img := pageImage(t, r)
+	if img.Object != 0 || img.BBox != (Box{X: 0, Y: 0, Width: 20, Height: 10}) {
+		t.Errorf("inline image test. want inline image at top left corner, got: %+v", img)
+	}
+	data, err := img.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pixels(t, data); fmt.Sprint(got) != fmt.Sprint([]color.RGBA{red, blue}) {
+		t.Errorf("inline image test. want red and blue, got: %v", got)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	for _, dict := range []string{
+		"/Width 8 /Height 8 /ColorSpace /DeviceGray /BitsPerComponent 1 /Filter /CCITTFaxDecode",
+		"/Width 1 /Height 1 /ColorSpace [/Separation /Spot /DeviceCMYK 6 0 R] /BitsPerComponent 8",
+		"/Width 1 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /LZWDecode",
+	} {
+		r := document(t, 0, "/Im5 Do", stream("/Subtype /Image "+dict, []byte{0}))
+		if _, err := pageImage(t, r).Data(); err == nil {
+			t.Errorf("unsupported image test. want error for %s", dict)
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	var c Catalog
+	logo := Image{Object: 12, Format: "png", Width: 3, Height: 1, BBox: Box{X: 1}}
+	inline := Image{Format: "png", Width: 2, Height: 2}
+
+	if c.Place(0, logo) {
+		t.Error("catalog test. new image should not be placed")
+	}
+	c.Add(0, logo, "img1.png", "img1.mask.png")
+	c.Add(0, inline, "img2.png", "")
+	if c.Place(0, inline) {
+		t.Error("catalog test. inline image should be saved every time")
+	}
+	c.Add(0, inline, "img3.png", "")
+	logo.BBox = Box{X: 2}
+	if !c.Place(4, logo) {
+		t.Error("catalog test. image object should be saved once")
+	}
+
+	if len(c.Images) != 3 {
+		t.Fatalf("catalog test. want 3 images, got: %d", len(c.Images))
+	}
+	entry := c.Images[0]
+	if entry.Object != 12 || entry.File != "img1.png" || entry.Mask != "img1.mask.png" || len(entry.Placements) != 2 ||
+		entry.Placements[1] != (Placement{Page: 5, BBox: Box{X: 2}}) {
+		t.Errorf("catalog test. want img1.png placed on pages 1 and 5, got: %+v", entry)
+	}
+}
@@ -0,0 +1,45 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Background contains settings for replacement of white page background
+type Background struct {
+	Transparent bool
+	// Color replaces white page background when set
+	Color *color.RGBA
+}
+
+// toAlpha turns white page background into transparency when background is replaced,
+// pages are rendered by go-fitz onto opaque white
+func (bg Background) toAlpha(img *image.RGBA) *image.RGBA {
+	if !bg.Transparent && bg.Color == nil {
+		return img
+	}
+	return imageutils.WhiteToAlpha(img)
+}
+
+// fill puts page with transparent background onto background color if one is set
+func (bg Background) fill(img *image.RGBA) *image.RGBA {
+	if bg.Color == nil {
+		return img
+	}
+	return imageutils.Flatten(img, *bg.Color)
+}
+
+// flattenFor composites transparent image onto white when output can't keep alpha channel.
+// Besides transparent background, alpha comes from transparent canvas color and shadows
+func (ps *Page) flattenFor(imgType string, img *image.RGBA) *image.RGBA {
+	if img.Opaque() {
+		return img
+	}
+	hasAlpha := imgType == "png" || imgType == "tiff" || imgType == "tif" || imgType == "webp"
+	if hasAlpha && (ps.Color.Mode == "" || ps.Color.Mode == "rgb") {
+		return img
+	}
+	return imageutils.Flatten(img, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+}
@@ -0,0 +1,51 @@
+package extractor
+
+import (
+	"image/color"
+	"testing"
+)
+
+type flattenTestCase struct {
+	comment string
+	imgType string
+	mode    string
+	// expectedVal is the pixel of transparent canvas around the page
+	expectedVal color.RGBA
+}
+
+var FlattenTestCase = []flattenTestCase{
+	{comment: "Png keeps alpha", imgType: "png", mode: "rgb", expectedVal: color.RGBA{}},
+	{comment: "Webp keeps alpha in default mode", imgType: "webp", expectedVal: color.RGBA{}},
+	{comment: "Jpg has no alpha", imgType: "jpg", mode: "rgb", expectedVal: white},
+	{comment: "Gray png has no alpha", imgType: "png", mode: "gray", expectedVal: white},
+}
+
+func TestFlattenFor(t *testing.T) {
+	// page on transparent canvas with shadow, background of the page itself is not replaced
+	canvas := Canvas{SizeX: 300, SizeY: 300, Align: "center", Margin: 20, Shadow: true}
+	img := canvas.apply(filled(100, 100, red))
+	for _, tc := range FlattenTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			ps := Page{Color: ColorMode{Mode: tc.mode}}
+			got := ps.flattenFor(tc.imgType, img)
+			if c := got.RGBAAt(5, 5); c != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, c)
+			}
+			if c := got.RGBAAt(150, 150); c != red {
+				t.Errorf("%s test. want page: %v, got: %v", tc.comment, red, c)
+			}
+			// shadow under the bottom right corner of the page is never black
+			if c := got.RGBAAt(278, 278); tc.expectedVal == white && (c.A != 255 || c.R < 128) {
+				t.Errorf("%s test. want shadow on white, got: %v", tc.comment, c)
+			}
+		})
+	}
+}
+
+func TestFlattenForOpaque(t *testing.T) {
+	img := filled(10, 10, red)
+	ps := Page{Color: ColorMode{Mode: "rgb"}}
+	if got := ps.flattenFor("jpg", img); got != img {
+		t.Error("opaque image test. want image as is")
+	}
+}
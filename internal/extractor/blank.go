@@ -0,0 +1,85 @@
+package extractor
+
+import (
+	"image"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gen2brain/go-fitz"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// blankNoise is standard deviation of luminance tolerated on blank pages (scanner noise, paper texture)
+const blankNoise = 10
+
+// Blank contains settings for detecting pages without content
+type Blank struct {
+	IsActive bool
+	// Threshold is maximal percent of ink pixels on a blank page
+	Threshold float64
+	// TextLayer additionally requires page text layer to be empty
+	TextLayer bool
+	// Move saves blank pages into Dir instead of skipping them
+	Move bool
+	// Dir is relative to SavePath
+	Dir string
+	// Found is shared by page copies of all workers
+	Found *BlankPages
+}
+
+// BlankPages collects numbers of blank pages found by workers
+type BlankPages struct {
+	mu    sync.Mutex
+	pages []int
+}
+
+// add records zero-based page number
+func (bp *BlankPages) add(pageNum int) {
+	if bp == nil {
+		return
+	}
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.pages = append(bp.pages, pageNum)
+}
+
+// Pages returns sorted zero-based numbers of blank pages
+func (bp *BlankPages) Pages() []int {
+	if bp == nil {
+		return nil
+	}
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	pages := append([]int(nil), bp.pages...)
+	sort.Ints(pages)
+	return pages
+}
+
+// check tells if the rendered page is blank. Besides ink coverage the luminance spread
+// is limited to what the allowed amount of black ink would produce, so pale photos
+// and light gray fills with no distinct ink are not taken for blank pages.
+// Text layer is checked for the whole document page, so a half of a split spread
+// with text on the other half is not blank
+func (b Blank) check(doc *fitz.Document, pageNum int, img *image.RGBA) (bool, error) {
+	if !b.IsActive {
+		return false, nil
+	}
+	coverage, stdDev := imageutils.InkStats(img)
+	share := b.Threshold / 100
+	if coverage > b.Threshold || stdDev > math.Sqrt(share*(1-share)*255*255+blankNoise*blankNoise) {
+		return false, nil
+	}
+	if b.TextLayer {
+		text, err := doc.Text(pageNum)
+		if err != nil {
+			return false, err
+		}
+		if strings.TrimSpace(text) != "" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
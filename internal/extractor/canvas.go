@@ -0,0 +1,58 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/draw"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Canvas contains settings for placing page with preserved aspect ratio on a canvas of fixed size
+type Canvas struct {
+	SizeX  int
+	SizeY  int
+	Color  color.RGBA
+	Align  imageutils.Anchor
+	Margin int
+	// Border is width in pixels of a frame drawn around the page
+	Border      int
+	BorderColor color.RGBA
+	Shadow      bool
+}
+
+// isActive checks if canvas size is set
+func (c Canvas) isActive() bool {
+	return c.SizeX > 0 && c.SizeY > 0
+}
+
+// apply fits image into canvas leaving room for margin, border and shadow
+func (c Canvas) apply(img *image.RGBA) *image.RGBA {
+	canvasRect := image.Rect(0, 0, c.SizeX, c.SizeY)
+	area := canvasRect.Inset(c.Margin)
+
+	shadowOffset, shadowBlur := 0, 0
+	if c.Shadow {
+		shadowOffset = max(min(c.SizeX, c.SizeY)*15/1000, 2)
+		shadowBlur = shadowOffset * 2
+		area.Max = area.Max.Sub(image.Pt(shadowOffset, shadowOffset))
+	}
+
+	page := imageutils.Fit(img, max(area.Dx()-2*c.Border, 1), max(area.Dy()-2*c.Border, 1))
+	if c.Border > 0 {
+		page = imageutils.Pad(page, c.Border, c.Border, c.Border, c.Border, c.BorderColor)
+	}
+
+	dstImg := image.NewRGBA(canvasRect)
+	draw.Draw(dstImg, canvasRect, image.NewUniform(c.Color), image.Point{}, draw.Src)
+
+	pos := c.Align.Place(area, page.Bounds().Size(), 0)
+	if c.Shadow {
+		shadow := imageutils.Shadow(page.Bounds().Size(), shadowBlur, 0.5)
+		imageutils.Overlay(dstImg, shadow, pos.Add(image.Pt(shadowOffset-shadowBlur, shadowOffset-shadowBlur)), 1)
+	}
+	imageutils.Overlay(dstImg, page, pos, 1)
+
+	return dstImg
+}
@@ -0,0 +1,107 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// colorBounds returns bounds of pixels of a given color
+func colorBounds(img *image.RGBA, c color.RGBA) image.Rectangle {
+	var bounds image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
+
+type canvasTestCase struct {
+	comment string
+	canvas  Canvas
+	// expectedVal is area of 300x200 page with its border on canvas
+	expectedVal image.Rectangle
+}
+
+var CanvasTestCase = []canvasTestCase{
+	{
+		comment:     "Square post",
+		canvas:      Canvas{SizeX: 1080, SizeY: 1080, Align: "center"},
+		expectedVal: image.Rect(0, 180, 1080, 900),
+	},
+	{
+		comment:     "Open Graph image",
+		canvas:      Canvas{SizeX: 1200, SizeY: 630, Align: "center"},
+		expectedVal: image.Rect(127, 0, 1072, 630),
+	},
+	{
+		comment:     "Open Graph image with margin",
+		canvas:      Canvas{SizeX: 1200, SizeY: 630, Align: "center", Margin: 30},
+		expectedVal: image.Rect(172, 30, 1027, 600),
+	},
+	{
+		comment:     "Square post with border",
+		canvas:      Canvas{SizeX: 1080, SizeY: 1080, Align: "center", Border: 6, BorderColor: red},
+		expectedVal: image.Rect(0, 178, 1080, 902),
+	},
+}
+
+func TestCanvasPlacement(t *testing.T) {
+	for _, tc := range CanvasTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			tc.canvas.Color = white
+			got := tc.canvas.apply(filled(300, 200, red))
+			if want := image.Rect(0, 0, tc.canvas.SizeX, tc.canvas.SizeY); got.Bounds() != want {
+				t.Fatalf("%s test. want: %v, got: %v", tc.comment, want, got.Bounds())
+			}
+			if bounds := colorBounds(got, red); bounds != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, bounds)
+			}
+			// page keeps its 3:2 aspect ratio inside the border
+			page := tc.expectedVal.Inset(tc.canvas.Border)
+			if page.Dx()*2 != page.Dy()*3 {
+				t.Errorf("%s test. want 3:2 page, got: %v", tc.comment, page)
+			}
+		})
+	}
+}
+
+type canvasAlignTestCase struct {
+	align imageutils.Anchor
+	// wideY is top of 200x100 page and tallX is left of 100x200 page on 1080x1080 canvas
+	wideY, tallX int
+}
+
+var CanvasAlignTestCase = []canvasAlignTestCase{
+	{align: "center", wideY: 270, tallX: 270},
+	{align: "top", wideY: 0, tallX: 270},
+	{align: "bottom", wideY: 540, tallX: 270},
+	{align: "left", wideY: 270, tallX: 0},
+	{align: "right", wideY: 270, tallX: 540},
+	{align: "top-left", wideY: 0, tallX: 0},
+	{align: "top-right", wideY: 0, tallX: 540},
+	{align: "bottom-left", wideY: 540, tallX: 0},
+	{align: "bottom-right", wideY: 540, tallX: 540},
+}
+
+func TestCanvasAlign(t *testing.T) {
+	for _, tc := range CanvasAlignTestCase {
+		t.Run(string(tc.align), func(t *testing.T) {
+			canvas := Canvas{SizeX: 1080, SizeY: 1080, Color: white, Align: tc.align}
+			wide := colorBounds(canvas.apply(filled(200, 100, red)), red)
+			if want := image.Rect(0, tc.wideY, 1080, tc.wideY+540); wide != want {
+				t.Errorf("%s wide page test. want: %v, got: %v", tc.align, want, wide)
+			}
+			tall := colorBounds(canvas.apply(filled(100, 200, red)), red)
+			if want := image.Rect(tc.tallX, 0, tc.tallX+540, 1080); tall != want {
+				t.Errorf("%s tall page test. want: %v, got: %v", tc.align, want, tall)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Caption contains settings for caption band added to pages
+type Caption struct {
+	// Template supports {doc}, {page} and {total} placeholders
+	Template string
+	DocName  string
+	Total    int
+	// Position of the band: top or bottom
+	Position string
+	// Align is text alignment inside the band: left, center or right
+	Align imageutils.Anchor
+	// Size is font size in percent of image width
+	Size       float64
+	Color      color.RGBA
+	Background color.RGBA
+}
+
+// text fills template placeholders for a page
+func (c Caption) text(pageNum int) string {
+	return strings.NewReplacer(
+		"{doc}", c.DocName,
+		"{page}", strconv.Itoa(pageNum+1),
+		"{total}", strconv.Itoa(c.Total),
+	).Replace(c.Template)
+}
+
+// apply extends canvas with caption band and renders caption text into it
+func (c Caption) apply(pageNum int, img *image.RGBA) (*image.RGBA, error) {
+	if c.Template == "" {
+		return img, nil
+	}
+	b := img.Bounds()
+	size := max(float64(b.Dx())*c.Size/100, 1)
+	textImg, err := imageutils.TextImage(c.text(pageNum), size, c.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	bandHeight := textImg.Bounds().Dy() * 9 / 5
+	band := image.Rect(0, 0, b.Dx(), bandHeight)
+	var dstImg *image.RGBA
+	if c.Position == "top" {
+		dstImg = imageutils.Pad(img, bandHeight, 0, 0, 0, c.Background)
+	} else {
+		dstImg = imageutils.Pad(img, 0, 0, bandHeight, 0, c.Background)
+		band = band.Add(image.Pt(0, b.Dy()))
+	}
+
+	pos := c.Align.Place(band, textImg.Bounds().Size(), bandHeight/2)
+	imageutils.Overlay(dstImg, textImg, pos, 1)
+	return dstImg, nil
+}
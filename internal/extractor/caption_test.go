@@ -0,0 +1,159 @@
+package extractor
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/dmikhr/pdfjuicer/configs"
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+type captionTextTestCase struct {
+	comment     string
+	template    string
+	pageNum     int
+	expectedVal string
+}
+
+var CaptionTextTestCase = []captionTextTestCase{
+	{comment: "All placeholders", template: "{doc} — p.{page}/{total}", pageNum: 2, expectedVal: "report — p.3/12"},
+	{comment: "Repeated placeholder", template: "{page} of {total}, page {page}", pageNum: 0, expectedVal: "1 of 12, page 1"},
+	{comment: "Plain text", template: "Confidential", pageNum: 5, expectedVal: "Confidential"},
+}
+
+func TestCaptionText(t *testing.T) {
+	for _, tc := range CaptionTextTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			c := Caption{Template: tc.template, DocName: "report", Total: 12}
+			if got := c.text(tc.pageNum); got != tc.expectedVal {
+				t.Errorf("%s test. want: %q, got: %q", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+// captionBandHeight returns height of band holding text of a given size
+func captionBandHeight(t *testing.T, text string, size float64) int {
+	t.Helper()
+	textImg, err := imageutils.TextImage(text, size, color.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return textImg.Bounds().Dy() * 9 / 5
+}
+
+type captionBandTestCase struct {
+	comment  string
+	position string
+	align    imageutils.Anchor
+}
+
+var CaptionBandTestCase = []captionBandTestCase{
+	{comment: "Footer", position: "bottom", align: "center"},
+	{comment: "Header", position: "top", align: "center"},
+	{comment: "Footer aligned left", position: "bottom", align: "left"},
+	{comment: "Header aligned right", position: "top", align: "right"},
+}
+
+func TestCaptionBand(t *testing.T) {
+	const width, height = 200, 100
+	black := color.RGBA{A: 255}
+	for _, tc := range CaptionBandTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			c := Caption{Template: "p.{page}/{total}", Total: 9, Position: tc.position, Align: tc.align,
+				Size: 10, Color: black, Background: red}
+			got, err := c.apply(0, filled(width, height, white))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			band := captionBandHeight(t, "p.1/9", 20)
+			if want := image.Rect(0, 0, width, height+band); got.Bounds() != want {
+				t.Fatalf("%s test. want: %v, got: %v", tc.comment, want, got.Bounds())
+			}
+			pageTop, bandTop := 0, height
+			if tc.position == "top" {
+				pageTop, bandTop = band, 0
+			}
+			for y := pageTop; y < pageTop+height; y++ {
+				for x := 0; x < width; x++ {
+					if c := got.RGBAAt(x, y); c != white {
+						t.Fatalf("%s test. page pixel %d,%d want: %v, got: %v", tc.comment, x, y, white, c)
+					}
+				}
+			}
+
+			// text is the only not red area of the band
+			text := image.Rectangle{}
+			for y := bandTop; y < bandTop+band; y++ {
+				for x := 0; x < width; x++ {
+					if got.RGBAAt(x, y) != red {
+						text = text.Union(image.Rect(x, y, x+1, y+1))
+					}
+				}
+			}
+			if text.Empty() {
+				t.Fatalf("%s test. want caption text in the band, got none", tc.comment)
+			}
+			center := (text.Min.X + text.Max.X) / 2
+			switch tc.align {
+			case "left":
+				if text.Min.X > band {
+					t.Errorf("%s test. want text at the left, got: %v", tc.comment, text)
+				}
+			case "right":
+				if text.Max.X < width-band {
+					t.Errorf("%s test. want text at the right, got: %v", tc.comment, text)
+				}
+			default:
+				if center < width/2-5 || center > width/2+5 {
+					t.Errorf("%s test. want text in the middle, got: %v", tc.comment, text)
+				}
+			}
+		})
+	}
+}
+
+func TestCaptionShiftsWordBoxes(t *testing.T) {
+	font := "/Font << /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> >>"
+	doc := newContentDoc(t, 300, 100, font, "BT /F1 10 Tf 10 50 Td (Caption) Tj ET\n")
+
+	// wordY extracts the page with caption at position and returns top of the word box
+	wordY := func(position string) int {
+		ps := Page{Doc: doc, Prefix: "page", ImgType: "png", SavePath: t.TempDir(), ScaleDown: config.ImgScaleDownDefault,
+			DPI: 72, Words: "json", Caption: Caption{Template: "p.{page}", Position: position, Size: 10}}
+		if position == "" {
+			ps.Caption = Caption{}
+		}
+		if err := ps.Extract(0); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(ps.SavePath, "page001.words.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var words struct {
+			Lines []wordBox `json:"lines"`
+		}
+		if err = json.Unmarshal(data, &words); err != nil {
+			t.Fatal(err)
+		}
+		if len(words.Lines) != 1 {
+			t.Fatalf("caption shift test. want one line, got: %v", words.Lines)
+		}
+		return words.Lines[0].Y
+	}
+
+	plain := wordY("")
+	if got := wordY("bottom"); got != plain {
+		t.Errorf("footer caption test. want: %d, got: %d", plain, got)
+	}
+	want := plain + captionBandHeight(t, "p.1", 30)
+	if got := wordY("top"); got != want {
+		t.Errorf("header caption test. want: %d, got: %d", want, got)
+	}
+}
@@ -0,0 +1,34 @@
+package extractor
+
+import (
+	"image"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// ColorMode contains settings for output color conversion
+type ColorMode struct {
+	// Mode is one of rgb, gray or bw
+	Mode string
+	// Method of conversion to black and white: threshold, otsu or dither
+	Method    string
+	Threshold int
+}
+
+// convert applies output color mode to the image right before encoding
+func (cm ColorMode) convert(img *image.RGBA) image.Image {
+	switch cm.Mode {
+	case "gray":
+		return imageutils.Grayscale(img)
+	case "bw":
+		gray := imageutils.Grayscale(img)
+		switch cm.Method {
+		case "dither":
+			return imageutils.Dither(gray)
+		case "otsu":
+			return imageutils.Threshold(gray, imageutils.OtsuThreshold(gray))
+		}
+		return imageutils.Threshold(gray, cm.Threshold)
+	}
+	return img
+}
@@ -0,0 +1,44 @@
+package extractor
+
+import (
+	"image"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// defaultDPI is resolution used by go-fitz Document.Image
+const defaultDPI = 300.0
+
+// Crop contains region cropped from every page and per page overrides
+type Crop struct {
+	Region *imageutils.Region
+	// Pages overrides region for zero-based page numbers
+	Pages map[int]imageutils.Region
+}
+
+// crop cuts configured region out of the rendered page before it is rotated,
+// so regions are measured on the page as shown. Returns the region and its rectangle
+func (ps *Page) crop(pageNum int, img *image.RGBA) (*image.RGBA, image.Rectangle) {
+	region, ok := ps.Crop.Pages[pageNum]
+	if !ok {
+		if ps.Crop.Region == nil {
+			return img, img.Bounds()
+		}
+		region = *ps.Crop.Region
+	}
+
+	box := region.Rect(img.Bounds(), ps.dpi())
+	if box.Empty() {
+		return img, img.Bounds()
+	}
+
+	return imageutils.Crop(img, box), box
+}
+
+// dpi returns rendering resolution
+func (ps *Page) dpi() float64 {
+	if ps.DPI > 0 {
+		return ps.DPI
+	}
+	return defaultDPI
+}
@@ -0,0 +1,93 @@
+package extractor
+
+import (
+	"image"
+	"sort"
+	"sync"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Hashes collects perceptual hashes of pages. Pages are collected in any order,
+// duplicates are found only when all pages are hashed, so results don't depend on workers
+type Hashes struct {
+	// Algorithm is ahash, dhash or phash
+	Algorithm string
+
+	mu     sync.Mutex
+	hashes map[int]uint64
+}
+
+// Collect hashes page image
+func (h *Hashes) Collect(pageNum int, img *image.RGBA) error {
+	var hash uint64
+	switch h.Algorithm {
+	case "ahash":
+		hash = imageutils.AHash(img)
+	case "dhash":
+		hash = imageutils.DHash(img)
+	default:
+		hash = imageutils.PHash(img)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.hashes == nil {
+		h.hashes = make(map[int]uint64)
+	}
+	h.hashes[pageNum] = hash
+	return nil
+}
+
+// Hashes returns collected hashes keyed by zero-based page number
+func (h *Hashes) Hashes() map[int]uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hashes := make(map[int]uint64, len(h.hashes))
+	for pageNum, hash := range h.hashes {
+		hashes[pageNum] = hash
+	}
+	return hashes
+}
+
+// Duplicates groups runs of consecutive pages, each within distance bits of the previous one.
+// Slide builds add content step by step, so the last page of a run is kept and
+// the result maps every other page of the run to it
+func (h *Hashes) Duplicates(distance int) map[int]int {
+	hashes := h.Hashes()
+	pageNums := make([]int, 0, len(hashes))
+	for pageNum := range hashes {
+		pageNums = append(pageNums, pageNum)
+	}
+	sort.Ints(pageNums)
+
+	duplicateOf := make(map[int]int)
+	runStart := 0
+	for i := 1; i <= len(pageNums); i++ {
+		if i < len(pageNums) && imageutils.Hamming(hashes[pageNums[i-1]], hashes[pageNums[i]]) <= distance {
+			continue
+		}
+		for _, pageNum := range pageNums[runStart : i-1] {
+			duplicateOf[pageNum] = pageNums[i-1]
+		}
+		runStart = i
+	}
+	return duplicateOf
+}
+
+// Dedupe contains duplicate pages found before extraction
+type Dedupe struct {
+	// DuplicateOf maps zero-based page to the kept page of its group
+	DuplicateOf map[int]int
+	// Drop skips duplicates, otherwise they are only grouped in the manifest
+	Drop bool
+}
+
+// skip checks if the page is a duplicate which is not saved
+func (d Dedupe) skip(pageNum int) bool {
+	if !d.Drop {
+		return false
+	}
+	_, ok := d.DuplicateOf[pageNum]
+	return ok
+}
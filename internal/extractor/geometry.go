@@ -0,0 +1,57 @@
+package extractor
+
+import (
+	"image"
+	"math"
+
+	"github.com/dmikhr/pdfjuicer/internal/layout"
+)
+
+// geometry maps points of the PDF page to pixels of the page image as it passes the pipeline:
+// x' = g[0]*x + g[1]*y + g[2], y' = g[3]*x + g[4]*y + g[5]
+type geometry [6]float64
+
+// newGeometry maps page points to pixels of the page rendered at dpi into image of rendered
+// size and then turned clockwise by quarter turns
+func newGeometry(dpi float64, rendered image.Point, turns int) geometry {
+	s := dpi / 72
+	w, h := float64(rendered.X), float64(rendered.Y)
+	switch ((turns % 4) + 4) % 4 {
+	case 1:
+		return geometry{0, -s, h, s, 0, 0}
+	case 2:
+		return geometry{-s, 0, w, 0, -s, h}
+	case 3:
+		return geometry{0, s, 0, -s, 0, w}
+	}
+	return geometry{s, 0, 0, 0, s, 0}
+}
+
+// translate moves mapped points by the offset
+func (g geometry) translate(offset image.Point) geometry {
+	g[2] += float64(offset.X)
+	g[5] += float64(offset.Y)
+	return g
+}
+
+// scale resizes mapped points from src to dst image size
+func (g geometry) scale(src, dst image.Point) geometry {
+	if src.X == 0 || src.Y == 0 {
+		return g
+	}
+	sx, sy := float64(dst.X)/float64(src.X), float64(dst.Y)/float64(src.Y)
+	return geometry{g[0] * sx, g[1] * sx, g[2] * sx, g[3] * sy, g[4] * sy, g[5] * sy}
+}
+
+// rect maps box in page points to a rectangle of image pixels
+func (g geometry) rect(b layout.Box) image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [][2]float64{{b.X, b.Y}, {b.X + b.Width, b.Y}, {b.X, b.Y + b.Height}, {b.X + b.Width, b.Y + b.Height}} {
+		x := g[0]*p[0] + g[1]*p[1] + g[2]
+		y := g[3]*p[0] + g[4]*p[1] + g[5]
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
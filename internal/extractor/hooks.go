@@ -0,0 +1,23 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// hook runs page hook command on a file written into output folder. Failures are collected
+// in hookErrs, so the remaining outputs of the page are still written
+func (ps *Page) hook(pageNum int, fname string) {
+	if ps.Exec == nil {
+		return
+	}
+	err := ps.Exec.Run(context.Background(), map[string]string{
+		"{path}": filepath.Join(ps.SavePath, fname),
+		"{page}": strconv.Itoa(pageNum + 1),
+	})
+	if err != nil {
+		ps.hookErrs = append(ps.hookErrs, fmt.Errorf("hook of page %d failed on %s: %w", pageNum+1, fname, err))
+	}
+}
@@ -0,0 +1,38 @@
+package extractor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	config "github.com/dmikhr/pdfjuicer/configs"
+	"github.com/dmikhr/pdfjuicer/internal/command"
+)
+
+func TestHookFailureKeepsOutputs(t *testing.T) {
+	font := "/Font << /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> >>"
+	doc := newContentDoc(t, 300, 100, font, "BT /F1 10 Tf 10 50 Td (Hook) Tj ET\n")
+	// hook fails on page image and succeeds on other files
+	hook := command.NewHook(command.Template{Args: []string{"sh", "-c", `case "$1" in *.png) exit 3;; esac`, "sh", "{path}"}}, 0, 1, io.Discard)
+	ps := Page{Doc: doc, Prefix: "page", ImgType: "png", SavePath: t.TempDir(), ScaleDown: config.ImgScaleDownDefault,
+		DPI: 72, Words: "json", Exec: hook, Thumbnails: Thumbnail{IsActive: true, ScaleDown: 2, Dir: "thumbs", Name: "{prefix}{page}"}}
+	if err := os.Mkdir(filepath.Join(ps.SavePath, "thumbs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ps.Extract(0)
+	if err == nil {
+		t.Fatal("hook failure test. want error")
+	}
+	// page image and thumbnail both fail
+	if n := strings.Count(err.Error(), "hook of page 1 failed"); n != 2 {
+		t.Errorf("hook failure test. want 2 hook errors, got: %v", err)
+	}
+	for _, fname := range []string{"page001.png", "page001.words.json", filepath.Join("thumbs", "page001.png")} {
+		if _, err := os.Stat(filepath.Join(ps.SavePath, fname)); err != nil {
+			t.Errorf("hook failure test. want %s written, got: %v", fname, err)
+		}
+	}
+}
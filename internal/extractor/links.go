@@ -0,0 +1,112 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"image"
+	"strings"
+
+	"github.com/dmikhr/pdfjuicer/internal/layout"
+)
+
+// linkRect is a link area in pixels of the page image
+type linkRect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// linkEntry describes a hyperlink in link map, Rect is missing when link area is unknown
+type linkEntry struct {
+	URI string `json:"uri"`
+	// Page is one-based target page of internal links
+	Page int       `json:"page,omitempty"`
+	Rect *linkRect `json:"rect,omitempty"`
+	// Estimated marks areas guessed from the link text
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// linkMap saves hyperlinks of the page with their areas mapped to the page image
+// as JSON and as HTML image map. Links outside of the image (e.g. on the other half
+// of a split spread) are left out
+func (ps *Page) linkMap(pageNum, num int, imageFName string, bounds image.Rectangle, geo geometry) error {
+	links, err := ps.pageLinks(pageNum)
+	if err != nil {
+		return err
+	}
+
+	entries := []linkEntry{}
+	for _, link := range links {
+		entry := linkEntry{URI: link.URI}
+		if link.Page > 0 {
+			entry.Page = ps.Split.logical(link.Page-1) + 1
+		}
+		if link.Found {
+			r := geo.rect(link.Box).Intersect(bounds)
+			if r.Empty() {
+				continue
+			}
+			entry.Rect = &linkRect{X: r.Min.X, Y: r.Min.Y, Width: r.Dx(), Height: r.Dy()}
+			entry.Estimated = link.Estimated
+		}
+		entries = append(entries, entry)
+	}
+
+	baseName := fmt.Sprintf("%s%03d%s.links", ps.Prefix, num+1, ps.Postfix)
+	data, err := json.MarshalIndent(struct {
+		Image  string      `json:"image"`
+		Width  int         `json:"width"`
+		Height int         `json:"height"`
+		Links  []linkEntry `json:"links"`
+	}{imageFName, bounds.Dx(), bounds.Dy(), entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = ps.writeSnippet(num, baseName+".json", data); err != nil {
+		return err
+	}
+	return ps.writeSnippet(num, baseName+".html", []byte(ps.imageMapHTML(num, imageFName, bounds, entries)))
+}
+
+// pageLinks reads link annotations of the page, links are located by their text
+// when annotations can't be read
+func (ps *Page) pageLinks(pageNum int) ([]layout.Link, error) {
+	if ps.Objects != nil {
+		if links, err := layout.AnnotLinks(ps.Objects, pageNum); err == nil {
+			return links, nil
+		}
+	}
+	lines, err := layout.Lines(ps.Doc, pageNum)
+	if err != nil {
+		return nil, err
+	}
+	return layout.LocateLinks(ps.Doc, pageNum, lines)
+}
+
+// imageMapHTML builds page <img> with <map> of link areas, internal links
+// point to images of their target pages. Unknown and estimated areas are left out
+// so a guessed area never becomes clickable
+func (ps *Page) imageMapHTML(num int, imageFName string, bounds image.Rectangle, entries []linkEntry) string {
+	mapName := strings.TrimSuffix(imageFName, "."+ps.ImgType)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<img src=\"%s\" width=\"%d\" height=\"%d\" usemap=\"#%s\" alt=\"Page %d\">\n",
+		html.EscapeString(imageFName), bounds.Dx(), bounds.Dy(), html.EscapeString(mapName), num+1)
+	fmt.Fprintf(&sb, "<map name=\"%s\">\n", html.EscapeString(mapName))
+	for _, entry := range entries {
+		if entry.Rect == nil || entry.Estimated {
+			continue
+		}
+		href := entry.URI
+		if entry.Page > 0 {
+			href = fmt.Sprintf("%s%03d%s.%s", ps.Prefix, entry.Page, ps.Postfix, ps.ImgType)
+		}
+		fmt.Fprintf(&sb, "  <area shape=\"rect\" coords=\"%d,%d,%d,%d\" href=\"%s\" alt=\"%s\">\n",
+			entry.Rect.X, entry.Rect.Y, entry.Rect.X+entry.Rect.Width, entry.Rect.Y+entry.Rect.Height,
+			html.EscapeString(href), html.EscapeString(entry.URI))
+	}
+	sb.WriteString("</map>\n")
+	return sb.String()
+}
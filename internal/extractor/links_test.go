@@ -0,0 +1,51 @@
+package extractor
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+type imageMapTestCase struct {
+	comment     string
+	entry       linkEntry
+	expectedVal bool
+}
+
+var ImageMapTestCase = []imageMapTestCase{
+	{
+		comment:     "Link from annotation",
+		entry:       linkEntry{URI: "https://example.com/", Rect: &linkRect{X: 10, Y: 20, Width: 30, Height: 40}},
+		expectedVal: true,
+	},
+	{
+		comment:     "Internal link points to page image",
+		entry:       linkEntry{Page: 3, Rect: &linkRect{X: 10, Y: 20, Width: 30, Height: 40}},
+		expectedVal: true,
+	},
+	{
+		comment:     "Estimated area",
+		entry:       linkEntry{URI: "https://example.com/", Rect: &linkRect{X: 10, Y: 20, Width: 30, Height: 40}, Estimated: true},
+		expectedVal: false,
+	},
+	{
+		comment:     "Link not found on the page",
+		entry:       linkEntry{URI: "https://example.com/"},
+		expectedVal: false,
+	},
+}
+
+func TestImageMapHTML(t *testing.T) {
+	for _, tc := range ImageMapTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			ps := Page{Prefix: "page", ImgType: "png"}
+			got := ps.imageMapHTML(0, "page001.png", image.Rect(0, 0, 100, 100), []linkEntry{tc.entry})
+			if strings.Contains(got, "<area") != tc.expectedVal {
+				t.Errorf("%s test. want area: %v, got: %s", tc.comment, tc.expectedVal, got)
+			}
+			if tc.entry.Page > 0 && !strings.Contains(got, `href="page003.png"`) {
+				t.Errorf("%s test. want link to page003.png, got: %s", tc.comment, got)
+			}
+		})
+	}
+}
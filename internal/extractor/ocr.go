@@ -0,0 +1,53 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dmikhr/pdfjuicer/internal/manifest"
+	"github.com/dmikhr/pdfjuicer/internal/ocr"
+)
+
+// OCR contains settings for recognizing text of saved page images
+type OCR struct {
+	Engine ocr.Engine
+	// MinText is number of non-space characters of a usable text layer, such pages are not recognized
+	MinText int
+	// Force recognizes pages regardless of their text layer
+	Force bool
+}
+
+// recognize runs OCR engine on saved page image, results are written next to it
+// with the same base name. Pages with usable text layer are skipped
+func (ps *Page) recognize(pageNum, num int, imageFName string) error {
+	if !ps.OCR.Force {
+		text, err := ps.Doc.Text(pageNum)
+		if err != nil {
+			return err
+		}
+		if len([]rune(strings.Join(strings.Fields(text), ""))) >= max(ps.OCR.MinText, 1) {
+			return nil
+		}
+	}
+
+	outBase := strings.TrimSuffix(imageFName, filepath.Ext(imageFName))
+	files, err := ps.OCR.Engine.Recognize(context.Background(),
+		filepath.Join(ps.SavePath, imageFName), filepath.Join(ps.SavePath, outBase))
+	if err != nil {
+		return fmt.Errorf("OCR of page %d failed: %w", num+1, err)
+	}
+	for _, f := range files {
+		rel, err := filepath.Rel(ps.SavePath, f)
+		if err != nil {
+			return err
+		}
+		ps.Manifest.AddFile(num, rel)
+		ps.hook(num, rel)
+	}
+	ps.Manifest.Update(num, func(entry *manifest.PageEntry) {
+		entry.OCR = true
+	})
+	return nil
+}
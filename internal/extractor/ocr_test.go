@@ -0,0 +1,56 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// countingEngine records recognized images without writing any files
+type countingEngine struct {
+	images []string
+}
+
+func (e *countingEngine) Recognize(_ context.Context, imagePath, _ string) ([]string, error) {
+	e.images = append(e.images, imagePath)
+	return nil, nil
+}
+
+type recognizeTestCase struct {
+	comment     string
+	text        string
+	force       bool
+	expectedVal bool
+}
+
+var RecognizeTestCase = []recognizeTestCase{
+	{comment: "Page without text layer", text: "", expectedVal: true},
+	{comment: "Text layer below threshold", text: "Nineteen characters!", expectedVal: true},
+	{comment: "Text layer at threshold", text: "Twenty characters okay", expectedVal: false},
+	{comment: "Spaces are not counted", text: "a b c d e f g h i j k l m n o p q r s", expectedVal: true},
+	{comment: "Forced recognition of page with text layer", text: "Twenty characters okay", force: true, expectedVal: true},
+}
+
+func TestRecognizeTextLayer(t *testing.T) {
+	font := "/Font << /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> >>"
+	for _, tc := range RecognizeTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			content := ""
+			if tc.text != "" {
+				content = fmt.Sprintf("BT /F1 10 Tf 10 50 Td (%s) Tj ET\n", tc.text)
+			}
+			engine := &countingEngine{}
+			ps := Page{
+				Doc:      newContentDoc(t, 300, 100, font, content),
+				SavePath: t.TempDir(),
+				OCR:      OCR{Engine: engine, MinText: 20, Force: tc.force},
+			}
+			if err := ps.recognize(0, 0, "page001.png"); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(engine.images) == 1; got != tc.expectedVal {
+				t.Errorf("%s test. want recognized: %t, got: %t", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
@@ -1,17 +1,19 @@
 package extractor
 
 import (
+	"errors"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
-	"os"
 	"path/filepath"
+	"strings"
 
 	config "github.com/dmikhr/pdfjuicer/configs"
 	"github.com/gen2brain/go-fitz"
 
+	"github.com/dmikhr/pdfjuicer/internal/command"
 	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+	"github.com/dmikhr/pdfjuicer/internal/manifest"
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
 )
 
 // Page contains settings for page extraction as image and pointer to source doc
@@ -25,6 +27,35 @@ type Page struct {
 	SizeX      int
 	SizeY      int
 	Thumbnails Thumbnail
+	Collector  Collector
+	// DPI overrides default rendering resolution when set
+	DPI        float64
+	Crop       Crop
+	Trim       Trim
+	Color      ColorMode
+	Background Background
+	Tone       Tone
+	Watermark  Watermark
+	Caption    Caption
+	Canvas     Canvas
+	Renditions Renditions
+	Split      Split
+	Rotate     Rotate
+	Blank      Blank
+	Dedupe     Dedupe
+	// Links saves per page maps of hyperlinks scaled to the page image
+	Links bool
+	// Objects reads link annotations of the source doc, link areas are estimated from page text when nil
+	Objects *pdf.Reader
+	// Words is format of per page word boxes: json, hocr, alto or empty for none
+	Words string
+	OCR   OCR
+	// Exec is run on every file written for a page, nil for none
+	Exec     *command.Hook
+	Manifest *manifest.Manifest
+
+	// hookErrs are failures of Exec during Extract
+	hookErrs []error
 }
 
 // Thumbnail contains settings for thumbnails
@@ -33,23 +64,115 @@ type Thumbnail struct {
 	ScaleDown float64
 	SizeX     int
 	SizeY     int
+	Canvas    Canvas
+	// ImgType overrides page image format when set
+	ImgType string
+	Quality int
+	// Dir is relative to SavePath
+	Dir string
+	// Name is a file name template without extension, e.g. thumbnail_{page}
+	Name string
+	// Collector receives thumbnails instead of saving them one file per page
+	Collector Collector
+}
+
+// Collector receives rendered pages when they are assembled into a combined output
+// instead of being saved one image per page
+type Collector interface {
+	Collect(pageNum int, img *image.RGBA) error
+}
+
+// Measure returns settings of a pre-pass handing pages to collector at full size,
+// cropped, rotated, deskewed and split like extracted pages, so results can be applied to them
+func (ps Page) Measure(collector Collector) Page {
+	return Page{
+		Doc:       ps.Doc,
+		ScaleDown: config.ImgScaleDownDefault,
+		DPI:       ps.DPI,
+		Crop:      ps.Crop,
+		Rotate:    Rotate{Turn: ps.Rotate.Turn, Deskew: ps.Rotate.Deskew},
+		Split:     ps.Split,
+		Collector: collector,
+	}
 }
 
-// Extract page from pdf document as image
+// Extract page from pdf document as image. Failed page hooks don't stop writing
+// the other outputs of the page, their errors are returned once all of them are written
 func (ps *Page) Extract(pageNum int) error {
-	srcImg, err := ps.Doc.Image(pageNum)
+	ps.hookErrs = nil
+	err := ps.extract(pageNum)
+	return errors.Join(append(ps.hookErrs, err)...)
+}
+
+// extract renders, prepares and saves page, splitting spreads
+func (ps *Page) extract(pageNum int) error {
+	srcImg, err := ps.render(pageNum)
 	if err != nil {
 		return err
 	}
+	turns, err := ps.turns(pageNum)
+	if err != nil {
+		return err
+	}
+	rendered := srcImg.Bounds().Size()
+	geo := newGeometry(ps.dpi(), rendered, turns)
+	full := imageutils.RotateRectRight(image.Rectangle{Max: rendered}, rendered, turns)
+
+	srcImg, box := ps.crop(pageNum, srcImg)
+	srcImg = imageutils.RotateRight(srcImg, turns)
+	origin := imageutils.RotateRectRight(box, rendered, turns).Min
+	parts, origins := []*image.RGBA{srcImg}, []image.Point{origin}
+	if ps.Split.isActive(pageNum) {
+		parts, origins = ps.Split.split(srcImg, origin)
+	}
+
+	for i, part := range parts {
+		num := ps.Split.logical(pageNum) + i
+		if part.Bounds().Size() != full.Size() {
+			ps.Manifest.Update(num, func(entry *manifest.PageEntry) {
+				entry.CropBox = manifest.NewBox(image.Rectangle{Min: origins[i], Max: origins[i].Add(part.Bounds().Size())})
+			})
+		}
+		if err = ps.process(pageNum, num, part, origins[i], geo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	imageFName := fmt.Sprintf("%s%03d%s.%s", ps.Prefix, pageNum+1, ps.Postfix, ps.ImgType)
-	imagePath := filepath.Join(ps.SavePath, imageFName)
-	f, err := os.Create(imagePath)
+// process prepares and saves a single logical page of document page pageNum, num is zero-based
+// page number accounting for split spreads. origin is position of the image on the rendered page,
+// geo maps page points to the rendered page
+func (ps *Page) process(pageNum, num int, srcImg *image.RGBA, origin image.Point, geo geometry) error {
+	if ps.Dedupe.skip(num) {
+		return nil
+	}
+	srcImg = ps.Rotate.deskew(srcImg)
+	blank, err := ps.Blank.check(ps.Doc, pageNum, srcImg)
 	if err != nil {
 		return err
 	}
+	if blank {
+		ps.Blank.Found.add(num)
+		ps.Manifest.Update(num, func(entry *manifest.PageEntry) {
+			entry.Blank = true
+		})
+		// combined outputs have no place for moved pages
+		if !ps.Blank.Move || ps.Collector != nil {
+			return nil
+		}
+	}
+
+	if ps.Trim.IsActive {
+		srcImg, origin = ps.trim(num, srcImg, origin)
+	}
+	geo = geo.translate(image.Point{}.Sub(origin))
+	// tone is applied to the page without background, so background color is kept as chosen
+	srcImg = ps.Background.toAlpha(srcImg)
+	srcImg = ps.Tone.apply(srcImg)
+	srcImg = ps.Background.fill(srcImg)
 
-	var dstImg, thumbnail *image.RGBA
+	var dstImg *image.RGBA
 	if ps.ScaleDown != config.ImgScaleDownDefault {
 		dstImg = imageutils.ScaleResize(srcImg, ps.ScaleDown)
 	} else if ps.SizeX > 0 && ps.SizeY > 0 {
@@ -57,48 +180,155 @@ func (ps *Page) Extract(pageNum int) error {
 	} else {
 		dstImg = srcImg
 	}
+	geo = geo.scale(srcImg.Bounds().Size(), dstImg.Bounds().Size())
+	dpi := ps.resolution(srcImg, dstImg)
 
-	err = saveImg(f, ps.ImgType, dstImg)
+	dstImg, err = ps.Watermark.apply(dstImg)
 	if err != nil {
 		return err
 	}
+	height := dstImg.Bounds().Dy()
+	dstImg, err = ps.Caption.apply(num, dstImg)
+	if err != nil {
+		return err
+	}
+	if ps.Caption.Position == "top" {
+		geo = geo.translate(image.Pt(0, dstImg.Bounds().Dy()-height))
+	}
+	if ps.Canvas.isActive() {
+		dstImg = ps.Canvas.apply(dstImg)
+	}
 
-	if ps.Thumbnails.IsActive {
-		f, err = os.Create(filepath.Join(ps.SavePath, config.ThumbnailsDir,
-			fmt.Sprintf("thumbnail_%03d.%s", pageNum+1, ps.ImgType)))
+	if ps.Collector != nil {
+		return ps.Collector.Collect(num, dstImg)
+	}
+
+	imageFName := fmt.Sprintf("%s%03d%s.%s", ps.Prefix, num+1, ps.Postfix, ps.ImgType)
+	if blank {
+		// moved blank pages get no renditions and thumbnails
+		return ps.save(num, filepath.Join(ps.Blank.Dir, imageFName), ps.ImgType, config.ImgQualityDefault, dpi, dstImg)
+	}
+	err = ps.save(num, imageFName, ps.ImgType, config.ImgQualityDefault, dpi, dstImg)
+	if err != nil {
+		return err
+	}
+
+	if ps.Links {
+		err = ps.linkMap(pageNum, num, imageFName, dstImg.Bounds(), geo)
 		if err != nil {
 			return err
 		}
-
-		if ps.Thumbnails.SizeX > 0 && ps.Thumbnails.SizeY > 0 {
-			thumbnail = imageutils.Resize(srcImg, ps.Thumbnails.SizeX, ps.Thumbnails.SizeY)
-		} else {
-			thumbnail = imageutils.ScaleResize(srcImg, ps.Thumbnails.ScaleDown)
+	}
+	if ps.Words != "" {
+		err = ps.wordBoxes(pageNum, num, imageFName, dstImg.Bounds(), geo)
+		if err != nil {
+			return err
+		}
+	}
+	if ps.OCR.Engine != nil {
+		err = ps.recognize(pageNum, num, imageFName)
+		if err != nil {
+			return err
 		}
-		err = saveImg(f, ps.ImgType, thumbnail)
+	}
+
+	if len(ps.Renditions.Items) > 0 {
+		err = ps.renditions(num, srcImg)
 		if err != nil {
 			return err
 		}
 	}
 
-	f.Close()
+	if ps.Thumbnails.IsActive {
+		thumbnail, err := ps.thumbnail(srcImg)
+		if err != nil {
+			return err
+		}
+		if ps.Thumbnails.Collector != nil {
+			return ps.Thumbnails.Collector.Collect(num, thumbnail)
+		}
+		imgType := ps.thumbnailType()
+		err = ps.save(num, filepath.Join(ps.Thumbnails.Dir, ps.thumbnailName(num)+"."+imgType),
+			imgType, ps.Thumbnails.Quality, ps.resolution(srcImg, thumbnail), thumbnail)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-// saveImg saves image in a given image format
-func saveImg(f *os.File, imgType string, dstImg *image.RGBA) error {
+// thumbnail makes thumbnail of the prepared page
+func (ps *Page) thumbnail(srcImg *image.RGBA) (*image.RGBA, error) {
+	var thumbnail *image.RGBA
 	var err error
+	if ps.Thumbnails.Canvas.isActive() {
+		// fitted into canvas after watermarking
+		thumbnail = srcImg
+	} else if ps.Thumbnails.SizeX > 0 && ps.Thumbnails.SizeY > 0 {
+		thumbnail = imageutils.Resize(srcImg, ps.Thumbnails.SizeX, ps.Thumbnails.SizeY)
+	} else {
+		thumbnail = imageutils.ScaleResize(srcImg, ps.Thumbnails.ScaleDown)
+	}
+	if ps.Watermark.Thumbnails {
+		thumbnail, err = ps.Watermark.apply(thumbnail)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if ps.Thumbnails.Canvas.isActive() {
+		thumbnail = ps.Thumbnails.Canvas.apply(thumbnail)
+	}
+	return thumbnail, nil
+}
 
-	switch imgType {
-	case "jpg", "jpeg":
-		err = jpeg.Encode(f, dstImg, &jpeg.Options{Quality: jpeg.DefaultQuality})
-	case "png":
-		err = png.Encode(f, dstImg)
+// thumbnailType returns thumbnails image format falling back to page format
+func (ps *Page) thumbnailType() string {
+	if ps.Thumbnails.ImgType != "" {
+		return ps.Thumbnails.ImgType
 	}
+	return ps.ImgType
+}
+
+// thumbnailName fills thumbnail file name template
+func (ps *Page) thumbnailName(pageNum int) string {
+	return strings.NewReplacer(
+		"{page}", fmt.Sprintf("%03d", pageNum+1),
+		"{prefix}", ps.Prefix,
+		"{postfix}", ps.Postfix,
+	).Replace(ps.Thumbnails.Name)
+}
+
+// Convert prepares image for encoding in a given format like page images are: it is flattened
+// when the format or output color mode has no alpha channel and converted to output color mode
+func (ps *Page) Convert(imgType string, img *image.RGBA) image.Image {
+	return ps.Color.convert(ps.flattenFor(imgType, img))
+}
 
+// save converts image to output color mode and saves it under a path relative to SavePath
+// with dpi recorded as its resolution
+func (ps *Page) save(pageNum int, fname, imgType string, quality int, dpi float64, img *image.RGBA) error {
+	err := imageutils.SaveDPI(filepath.Join(ps.SavePath, fname), imgType, ps.Convert(imgType, img), quality, dpi)
 	if err != nil {
 		return err
 	}
+	ps.Manifest.AddFile(pageNum, fname)
+	ps.hook(pageNum, fname)
 	return nil
 }
+
+// resolution returns DPI of image dst resized from the prepared page src
+func (ps *Page) resolution(src, dst *image.RGBA) float64 {
+	if src.Bounds().Dx() == 0 {
+		return ps.dpi()
+	}
+	return ps.dpi() * float64(dst.Bounds().Dx()) / float64(src.Bounds().Dx())
+}
+
+// render rasterizes page at configured DPI or at go-fitz default
+func (ps *Page) render(pageNum int) (*image.RGBA, error) {
+	if ps.DPI > 0 {
+		return ps.Doc.ImageDPI(pageNum, ps.DPI)
+	}
+	return ps.Doc.Image(pageNum)
+}
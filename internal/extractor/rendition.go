@@ -0,0 +1,148 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	config "github.com/dmikhr/pdfjuicer/configs"
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Rendition is an additional width of the page saved for responsive image sets
+type Rendition struct {
+	Width   int
+	ImgType string
+}
+
+// Renditions contains settings for saving several widths of every page from a single render
+type Renditions struct {
+	Items []Rendition
+	// Srcset is a format of per page snippet listing renditions: html, json or empty for none
+	Srcset string
+}
+
+// renditionFile describes saved rendition in srcset snippets
+type renditionFile struct {
+	Path   string `json:"path"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+}
+
+// mimeTypes maps image formats to types used in <source type=...>
+var mimeTypes = map[string]string{
+	"png":  "image/png",
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"webp": "image/webp",
+	"tiff": "image/tiff",
+	"tif":  "image/tiff",
+}
+
+// renditions resizes prepared page to every rendition width and saves results with optional srcset snippet
+func (ps *Page) renditions(pageNum int, srcImg *image.RGBA) error {
+	var files []renditionFile
+	for _, r := range ps.Renditions.Items {
+		bounds := srcImg.Bounds()
+		height := max(int(float64(r.Width)*float64(bounds.Dy())/float64(bounds.Dx())+0.5), 1)
+		img := imageutils.Resize(srcImg, r.Width, height)
+
+		img, err := ps.Watermark.apply(img)
+		if err != nil {
+			return err
+		}
+		img, err = ps.Caption.apply(pageNum, img)
+		if err != nil {
+			return err
+		}
+
+		fname := fmt.Sprintf("%s%03d%s-%dw.%s", ps.Prefix, pageNum+1, ps.Postfix, r.Width, r.ImgType)
+		if err := ps.save(pageNum, fname, r.ImgType, config.ImgQualityDefault, ps.resolution(srcImg, img), img); err != nil {
+			return err
+		}
+		files = append(files, renditionFile{
+			Path:   fname,
+			Width:  img.Bounds().Dx(),
+			Height: img.Bounds().Dy(),
+			Format: r.ImgType,
+		})
+	}
+
+	baseName := fmt.Sprintf("%s%03d%s.srcset", ps.Prefix, pageNum+1, ps.Postfix)
+	switch ps.Renditions.Srcset {
+	case "json":
+		data, err := json.MarshalIndent(files, "", "  ")
+		if err != nil {
+			return err
+		}
+		return ps.writeSnippet(pageNum, baseName+".json", data)
+	case "html":
+		return ps.writeSnippet(pageNum, baseName+".html", []byte(pictureHTML(pageNum, files)))
+	}
+	return nil
+}
+
+// writeSnippet saves a text file of the page (srcset snippet, link map or word boxes) next to
+// page images, adds it to the manifest and passes it to the page hook
+func (ps *Page) writeSnippet(pageNum int, fname string, data []byte) error {
+	err := os.WriteFile(filepath.Join(ps.SavePath, fname), data, 0o644)
+	if err != nil {
+		return err
+	}
+	ps.Manifest.AddFile(pageNum, fname)
+	ps.hook(pageNum, fname)
+	return nil
+}
+
+// pictureHTML builds <picture> element with a <source> per format,
+// jpg or png renditions are used as <img> fallback for browsers without format support
+func pictureHTML(pageNum int, files []renditionFile) string {
+	var formats []string
+	byFormat := make(map[string][]renditionFile)
+	for _, f := range files {
+		if _, ok := byFormat[f.Format]; !ok {
+			formats = append(formats, f.Format)
+		}
+		byFormat[f.Format] = append(byFormat[f.Format], f)
+	}
+
+	fallback := formats[len(formats)-1]
+	for _, format := range []string{"png", "jpeg", "jpg"} {
+		if _, ok := byFormat[format]; ok {
+			fallback = format
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<picture>\n")
+	for _, format := range formats {
+		if format == fallback {
+			continue
+		}
+		fmt.Fprintf(&sb, "  <source type=\"%s\" srcset=\"%s\">\n", mimeTypes[format], srcset(byFormat[format]))
+	}
+	largest := byFormat[fallback][0]
+	for _, f := range byFormat[fallback] {
+		if f.Width > largest.Width {
+			largest = f
+		}
+	}
+	fmt.Fprintf(&sb, "  <img src=\"%s\" srcset=\"%s\" width=\"%d\" height=\"%d\" alt=\"Page %d\">\n",
+		html.EscapeString(largest.Path), srcset(byFormat[fallback]), largest.Width, largest.Height, pageNum+1)
+	sb.WriteString("</picture>\n")
+	return sb.String()
+}
+
+// srcset lists files with their width descriptors
+func srcset(files []renditionFile) string {
+	entries := make([]string, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, fmt.Sprintf("%s %dw", html.EscapeString(f.Path), f.Width))
+	}
+	return strings.Join(entries, ", ")
+}
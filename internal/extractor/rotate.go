@@ -0,0 +1,80 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Rotate contains settings for straightening pages
+type Rotate struct {
+	// Turn is clockwise rotation: 90, 180, 270 or auto to make text upright
+	Turn string
+	// Deskew corrects small skew of scanned pages
+	Deskew bool
+}
+
+// glyphRe matches glyphs in go-fitz SVG output, matrix starts with glyph direction
+var glyphRe = regexp.MustCompile(`<use data-text="[^"]*" xlink:href="#font_[^"]*" transform="matrix\(([^,]+),([^,]+),`)
+
+// turns returns number of clockwise quarter turns of rendered page. Page /Rotate is already
+// applied by renderer, auto mode additionally turns page so the most of its text layer is upright
+func (ps *Page) turns(pageNum int) (int, error) {
+	switch ps.Rotate.Turn {
+	case "":
+		return 0, nil
+	case "auto":
+		return ps.textTurns(pageNum)
+	}
+	degrees, err := strconv.Atoi(ps.Rotate.Turn)
+	if err != nil {
+		return 0, err
+	}
+	return degrees / 90, nil
+}
+
+// textTurns finds dominant direction of glyphs and returns number of clockwise
+// quarter turns making it left to right, pages without text are not turned
+func (ps *Page) textTurns(pageNum int) (int, error) {
+	svg, err := ps.Doc.SVG(pageNum)
+	if err != nil {
+		return 0, err
+	}
+
+	var directions [4]int
+	for _, m := range glyphRe.FindAllStringSubmatch(svg, -1) {
+		a, errA := strconv.ParseFloat(m[1], 64)
+		b, errB := strconv.ParseFloat(m[2], 64)
+		if errA != nil || errB != nil {
+			continue
+		}
+		// angle of glyph baseline in page coordinates with y axis pointing down
+		quarter := int(math.Round(math.Atan2(b, a)/(math.Pi/2))+4) % 4
+		directions[quarter]++
+	}
+
+	dominant := 0
+	for quarter, count := range directions {
+		if count > directions[dominant] {
+			dominant = quarter
+		}
+	}
+	// baseline turned clockwise by quarters is fixed by the same number of counterclockwise turns
+	return (4 - dominant) % 4, nil
+}
+
+// deskew estimates skew of a page and rotates it back, uncovered corners are filled with white
+func (r Rotate) deskew(img *image.RGBA) *image.RGBA {
+	if !r.Deskew {
+		return img
+	}
+	angle := imageutils.SkewAngle(img)
+	if angle == 0 {
+		return img
+	}
+	return imageutils.Deskew(img, angle, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+}
@@ -0,0 +1,96 @@
+package extractor
+
+import (
+	"image"
+
+	"github.com/gen2brain/go-fitz"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Split contains settings for cutting scanned two-page spreads into single pages
+type Split struct {
+	// Mode is vertical (left and right halves), horizontal (top and bottom halves)
+	// or auto (left and right halves of landscape pages only)
+	Mode string
+	// Gutter cuts at detected gutter instead of exact center
+	Gutter bool
+	// RTL puts right half first for right-to-left reading order
+	RTL bool
+	// Spreads lists zero-based pages to split
+	Spreads map[int]bool
+	// First maps zero-based page to zero-based number of its first logical page
+	First map[int]int
+	// Total is number of logical pages in the document
+	Total int
+}
+
+// NewSplit finds spreads in the document and numbers logical pages, so halves
+// get consecutive numbers regardless of the order pages are processed in
+func NewSplit(doc *fitz.Document, mode string, gutter, rtl bool) (Split, error) {
+	s := Split{
+		Mode:    mode,
+		Gutter:  gutter,
+		RTL:     rtl,
+		Spreads: make(map[int]bool),
+		First:   make(map[int]int),
+	}
+	for pageNum := 0; pageNum < doc.NumPage(); pageNum++ {
+		s.First[pageNum] = s.Total
+		s.Total++
+		if mode == "auto" {
+			bound, err := doc.Bound(pageNum)
+			if err != nil {
+				return Split{}, err
+			}
+			if bound.Dx() <= bound.Dy() {
+				continue
+			}
+		}
+		s.Spreads[pageNum] = true
+		s.Total++
+	}
+	return s, nil
+}
+
+// isActive checks if the page is cut into halves
+func (s Split) isActive(pageNum int) bool {
+	return s.Spreads[pageNum]
+}
+
+// logical returns zero-based number of the first logical page of a document page
+func (s Split) logical(pageNum int) int {
+	if first, ok := s.First[pageNum]; ok {
+		return first
+	}
+	return pageNum
+}
+
+// split cuts page into halves in reading order and returns them with their positions on the page
+func (s Split) split(img *image.RGBA, origin image.Point) ([]*image.RGBA, []image.Point) {
+	b := img.Bounds()
+	vertical := s.Mode != "horizontal"
+
+	var cut int
+	switch {
+	case s.Gutter:
+		cut = imageutils.Gutter(img, vertical)
+	case vertical:
+		cut = b.Min.X + b.Dx()/2
+	default:
+		cut = b.Min.Y + b.Dy()/2
+	}
+
+	first, second := b, b
+	if vertical {
+		first.Max.X, second.Min.X = cut, cut
+	} else {
+		first.Max.Y, second.Min.Y = cut, cut
+	}
+	if s.RTL && vertical {
+		first, second = second, first
+	}
+
+	return []*image.RGBA{imageutils.Crop(img, first), imageutils.Crop(img, second)},
+		[]image.Point{origin.Add(first.Min.Sub(b.Min)), origin.Add(second.Min.Sub(b.Min))}
+}
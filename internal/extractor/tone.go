@@ -0,0 +1,25 @@
+package extractor
+
+import (
+	"image"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Tone contains settings for rendering pages in dark colors
+type Tone struct {
+	Invert bool
+	// DarkMode inverts lightness keeping hue, so charts stay readable
+	DarkMode bool
+}
+
+// apply inverts page colors or lightness
+func (t Tone) apply(img *image.RGBA) *image.RGBA {
+	if t.DarkMode {
+		return imageutils.InvertLightness(img)
+	}
+	if t.Invert {
+		return imageutils.Invert(img)
+	}
+	return img
+}
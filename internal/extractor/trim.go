@@ -0,0 +1,63 @@
+package extractor
+
+import (
+	"image"
+	"sync"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+	"github.com/dmikhr/pdfjuicer/internal/manifest"
+)
+
+// Trim contains settings for cropping pages to their content
+type Trim struct {
+	IsActive  bool
+	Tolerance int
+	Padding   int
+	// Box is applied to every page instead of detected content bounds when set
+	Box image.Rectangle
+}
+
+// trim crops page to its content box expanded by padding, blank pages are left as is.
+// origin is position of the image on the page if it was already cropped, position
+// of the trimmed image is returned with it
+func (ps *Page) trim(pageNum int, img *image.RGBA, origin image.Point) (*image.RGBA, image.Point) {
+	box := ps.Trim.Box
+	if box.Empty() {
+		box = imageutils.ContentBounds(img, ps.Trim.Tolerance)
+	}
+	if box.Empty() {
+		return img, origin
+	}
+	box = box.Inset(-ps.Trim.Padding).Intersect(img.Bounds())
+
+	ps.Manifest.Update(pageNum, func(entry *manifest.PageEntry) {
+		entry.CropBox = manifest.NewBox(box.Add(origin))
+	})
+	return imageutils.Crop(img, box), origin.Add(box.Min)
+}
+
+// ContentBounds collects union of content boxes across pages, so the same trim
+// can be applied to the whole document
+type ContentBounds struct {
+	Tolerance int
+
+	mu    sync.Mutex
+	union image.Rectangle
+}
+
+// Collect adds content box of a page to the union
+func (cb *ContentBounds) Collect(_ int, img *image.RGBA) error {
+	box := imageutils.ContentBounds(img, cb.Tolerance)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.union = cb.union.Union(box)
+	return nil
+}
+
+// Union returns content box covering all collected pages
+func (cb *ContentBounds) Union() image.Rectangle {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.union
+}
@@ -0,0 +1,125 @@
+package extractor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/gen2brain/go-fitz"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// newTestDoc builds a single page PDF with black rectangles, page and rectangles
+// are in points with y axis pointing down, so at 72 DPI they are pixels of rendered page
+func newTestDoc(t *testing.T, width, height int, rects ...image.Rectangle) *fitz.Document {
+	t.Helper()
+	var content bytes.Buffer
+	for _, r := range rects {
+		fmt.Fprintf(&content, "0 0 0 rg %d %d %d %d re f\n", r.Min.X, height-r.Max.Y, r.Dx(), r.Dy())
+	}
+	return newContentDoc(t, width, height, "", content.String())
+}
+
+// newContentDoc builds a single page PDF with given page resources and content stream
+func newContentDoc(t *testing.T, width, height int, resources, content string) *fitz.Document {
+	t.Helper()
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << %s >> /Contents 4 0 R >>",
+			width, height, resources),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	doc, err := fitz.NewFromMemory(pdf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	return doc
+}
+
+type measureTestCase struct {
+	comment     string
+	rects       []image.Rectangle
+	crop        *imageutils.Region
+	turn        string
+	split       string
+	expectedVal image.Rectangle
+}
+
+var MeasureTestCase = []measureTestCase{
+	{
+		comment:     "Page as is",
+		rects:       []image.Rectangle{image.Rect(20, 10, 60, 30)},
+		expectedVal: image.Rect(20, 10, 60, 30),
+	},
+	{
+		comment:     "Union of content on the page",
+		rects:       []image.Rectangle{image.Rect(20, 10, 60, 30), image.Rect(120, 50, 140, 80)},
+		expectedVal: image.Rect(20, 10, 140, 80),
+	},
+	{
+		comment:     "Rotated page",
+		rects:       []image.Rectangle{image.Rect(20, 10, 60, 30)},
+		turn:        "90",
+		expectedVal: image.Rect(70, 20, 90, 60),
+	},
+	{
+		comment: "Page cropped before rotation",
+		rects:   []image.Rectangle{image.Rect(20, 10, 60, 30), image.Rect(120, 50, 140, 80)},
+		crop: &imageutils.Region{
+			X: imageutils.Length{Value: 50, Unit: imageutils.Percent}, Y: imageutils.Length{Value: 0},
+			W: imageutils.Length{Value: 100}, H: imageutils.Length{Value: 100},
+		},
+		turn:        "90",
+		expectedVal: image.Rect(20, 20, 50, 40),
+	},
+	{
+		comment:     "Halves of a spread",
+		rects:       []image.Rectangle{image.Rect(20, 10, 60, 30), image.Rect(120, 50, 140, 80)},
+		split:       "vertical",
+		expectedVal: image.Rect(20, 10, 60, 80),
+	},
+}
+
+func TestMeasureContentBounds(t *testing.T) {
+	for _, tc := range MeasureTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			doc := newTestDoc(t, 200, 100, tc.rects...)
+			page := Page{Doc: doc, DPI: 72, ScaleDown: 2, Crop: Crop{Region: tc.crop}, Rotate: Rotate{Turn: tc.turn}}
+			if tc.split != "" {
+				split, err := NewSplit(doc, tc.split, false, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				page.Split = split
+			}
+
+			bounds := &ContentBounds{Tolerance: 10}
+			measure := page.Measure(bounds)
+			if err := measure.Extract(0); err != nil {
+				t.Fatal(err)
+			}
+			if got := bounds.Union(); got != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
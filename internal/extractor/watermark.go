@@ -0,0 +1,85 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/font/opentype"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Watermark contains text and image overlays stamped onto pages after resizing
+type Watermark struct {
+	Text string
+	// Font is used for text instead of embedded Go font when set
+	Font *opentype.Font
+	// TextSize is font size in percent of image width
+	TextSize     float64
+	TextColor    color.RGBA
+	TextOpacity  float64
+	TextAngle    float64
+	TextPosition imageutils.Anchor
+
+	Image         *image.RGBA
+	ImagePosition imageutils.Anchor
+	// ImageScale is width of the stamped image in percent of page image width
+	ImageScale   float64
+	ImageOpacity float64
+
+	// Thumbnails enables stamping of thumbnails as well
+	Thumbnails bool
+}
+
+// isActive checks if there is anything to stamp
+func (wm Watermark) isActive() bool {
+	return wm.Text != "" || wm.Image != nil
+}
+
+// apply stamps overlays onto a copy of the image
+func (wm Watermark) apply(img *image.RGBA) (*image.RGBA, error) {
+	if !wm.isActive() {
+		return img, nil
+	}
+	b := img.Bounds()
+	dstImg := imageutils.Clone(img)
+	margin := min(b.Dx(), b.Dy()) * 3 / 100
+
+	if wm.Image != nil {
+		width := max(int(float64(b.Dx())*wm.ImageScale/100), 1)
+		height := max(int(math.Round(float64(width)*float64(wm.Image.Bounds().Dy())/float64(wm.Image.Bounds().Dx()))), 1)
+		stamp := imageutils.Resize(wm.Image, width, height)
+		stampAt(dstImg, stamp, wm.ImagePosition, margin, wm.ImageOpacity)
+	}
+
+	if wm.Text != "" {
+		size := max(float64(b.Dx())*wm.TextSize/100, 1)
+		var stamp *image.RGBA
+		var err error
+		if wm.Font != nil {
+			stamp, err = imageutils.FontTextImage(wm.Font, wm.Text, size, wm.TextColor)
+		} else {
+			stamp, err = imageutils.TextImage(wm.Text, size, wm.TextColor)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if wm.TextAngle != 0 {
+			stamp = imageutils.Rotate(stamp, wm.TextAngle)
+		}
+		stampAt(dstImg, stamp, wm.TextPosition, margin, wm.TextOpacity)
+	}
+
+	return dstImg, nil
+}
+
+// stampAt draws stamp at position or repeats it over the image with gaps of half its size when tiled
+func stampAt(dst, stamp *image.RGBA, position imageutils.Anchor, margin int, opacity float64) {
+	size := stamp.Bounds().Size()
+	if position == imageutils.Tile {
+		imageutils.OverlayTiled(dst, stamp, size.Div(2), opacity)
+		return
+	}
+	imageutils.Overlay(dst, stamp, position.Place(dst.Bounds(), size, margin), opacity)
+}
@@ -0,0 +1,120 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+var (
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red   = color.RGBA{R: 255, A: 255}
+)
+
+// filled returns image of given size filled with color
+func filled(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+type watermarkImageTestCase struct {
+	comment  string
+	position imageutils.Anchor
+	// expectedVal is area of 100x100 page covered by 20x20 stamp with 3 pixels margin
+	expectedVal image.Rectangle
+}
+
+var WatermarkImageTestCase = []watermarkImageTestCase{
+	{comment: "Top left", position: "top-left", expectedVal: image.Rect(3, 3, 23, 23)},
+	{comment: "Center", position: "center", expectedVal: image.Rect(40, 40, 60, 60)},
+	{comment: "Bottom right", position: "bottom-right", expectedVal: image.Rect(77, 77, 97, 97)},
+	{comment: "Right", position: "right", expectedVal: image.Rect(77, 40, 97, 60)},
+}
+
+func TestWatermarkImagePosition(t *testing.T) {
+	for _, tc := range WatermarkImageTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			wm := Watermark{Image: filled(10, 10, red), ImagePosition: tc.position, ImageScale: 20, ImageOpacity: 1}
+			got, err := wm.apply(filled(100, 100, white))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bounds := imageutils.ContentBounds(got, 10); bounds != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, bounds)
+			}
+			if c := got.RGBAAt(tc.expectedVal.Min.X, tc.expectedVal.Min.Y); c != red {
+				t.Errorf("%s test. want: %v stamp, got: %v", tc.comment, red, c)
+			}
+		})
+	}
+}
+
+type watermarkOpacityTestCase struct {
+	comment string
+	opacity float64
+	// expectedVal is green channel of red stamp over white page
+	expectedVal uint8
+}
+
+var WatermarkOpacityTestCase = []watermarkOpacityTestCase{
+	{comment: "Opaque", opacity: 1, expectedVal: 0},
+	{comment: "Half transparent", opacity: 0.5, expectedVal: 127},
+	{comment: "Invisible", opacity: 0, expectedVal: 255},
+}
+
+func TestWatermarkOpacity(t *testing.T) {
+	for _, tc := range WatermarkOpacityTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			wm := Watermark{Image: filled(10, 10, red), ImagePosition: "center", ImageScale: 20, ImageOpacity: tc.opacity}
+			got, err := wm.apply(filled(100, 100, white))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := got.RGBAAt(50, 50)
+			if c.R != 255 || c.A != 255 || max(c.G, tc.expectedVal)-min(c.G, tc.expectedVal) > 1 || c.B != c.G {
+				t.Errorf("%s test. want: green %d, got: %v", tc.comment, tc.expectedVal, c)
+			}
+		})
+	}
+}
+
+func TestWatermarkTiled(t *testing.T) {
+	wm := Watermark{Image: filled(10, 10, red), ImagePosition: imageutils.Tile, ImageScale: 20, ImageOpacity: 1}
+	got, err := wm.apply(filled(100, 100, white))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20x20 copies are repeated every 30 pixels, the last ones are cut by the page edge
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			want := white
+			if x%30 < 20 && y%30 < 20 {
+				want = red
+			}
+			if c := got.RGBAAt(x, y); c != want {
+				t.Fatalf("tiled watermark test. pixel %d,%d want: %v, got: %v", x, y, want, c)
+			}
+		}
+	}
+}
+
+func TestWatermarkText(t *testing.T) {
+	page := filled(200, 100, white)
+	wm := Watermark{Text: "DRAFT", TextSize: 10, TextColor: red, TextOpacity: 1, TextPosition: "bottom-left"}
+	got, err := wm.apply(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounds := imageutils.ContentBounds(got, 10)
+	// glyphs of 20 pixels text lie within 3 pixels margin at the bottom left
+	if bounds.Empty() || bounds.Min.X < 3 || bounds.Max.Y > 97 || bounds.Max.X > 100 || bounds.Min.Y < 70 {
+		t.Errorf("text watermark test. want text at bottom left, got: %v", bounds)
+	}
+	if page.RGBAAt(bounds.Min.X, bounds.Max.Y-1) != white {
+		t.Error("text watermark test. want source page unchanged")
+	}
+}
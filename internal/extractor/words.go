@@ -0,0 +1,160 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"image"
+	"strings"
+
+	"github.com/dmikhr/pdfjuicer/internal/layout"
+)
+
+// wordBox is a word or line of the text layer in pixels of the page image
+type wordBox struct {
+	Text   string    `json:"text"`
+	X      int       `json:"x"`
+	Y      int       `json:"y"`
+	Width  int       `json:"width"`
+	Height int       `json:"height"`
+	Words  []wordBox `json:"words,omitempty"`
+}
+
+// rect returns box as image rectangle
+func (w wordBox) rect() image.Rectangle {
+	return image.Rect(w.X, w.Y, w.X+w.Width, w.Y+w.Height)
+}
+
+// newWordBox creates box of a text with given rectangle
+func newWordBox(text string, r image.Rectangle) wordBox {
+	return wordBox{Text: text, X: r.Min.X, Y: r.Min.Y, Width: r.Dx(), Height: r.Dy()}
+}
+
+// wordBoxes saves text layer of the page as lines and words with boxes mapped to the page image
+// in json, hocr or alto format. Words outside of the image (e.g. on the other half
+// of a split spread) are left out
+func (ps *Page) wordBoxes(pageNum, num int, imageFName string, bounds image.Rectangle, geo geometry) error {
+	lines, err := layout.Lines(ps.Doc, pageNum)
+	if err != nil {
+		return err
+	}
+
+	boxes := []wordBox{}
+	for _, line := range lines {
+		var lineRect image.Rectangle
+		var words []wordBox
+		for _, word := range line.Words {
+			r := geo.rect(word.Box).Intersect(bounds)
+			if r.Empty() {
+				continue
+			}
+			words = append(words, newWordBox(word.Text, r))
+			lineRect = lineRect.Union(r)
+		}
+		if len(words) == 0 {
+			continue
+		}
+		texts := make([]string, len(words))
+		for i, w := range words {
+			texts[i] = w.Text
+		}
+		lineBox := newWordBox(strings.Join(texts, " "), lineRect)
+		lineBox.Words = words
+		boxes = append(boxes, lineBox)
+	}
+
+	baseName := fmt.Sprintf("%s%03d%s", ps.Prefix, num+1, ps.Postfix)
+	switch ps.Words {
+	case "hocr":
+		return ps.writeSnippet(num, baseName+".hocr", []byte(hocr(num, imageFName, bounds, boxes)))
+	case "alto":
+		return ps.writeSnippet(num, baseName+".alto.xml", []byte(alto(num, imageFName, bounds, boxes)))
+	}
+	data, err := json.MarshalIndent(struct {
+		Image  string    `json:"image"`
+		Width  int       `json:"width"`
+		Height int       `json:"height"`
+		Lines  []wordBox `json:"lines"`
+	}{imageFName, bounds.Dx(), bounds.Dy(), boxes}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ps.writeSnippet(num, baseName+".words.json", data)
+}
+
+// hocr formats lines as hOCR page
+func hocr(num int, imageFName string, bounds image.Rectangle, lines []wordBox) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en" lang="en">
+<head>
+<title></title>
+<meta http-equiv="Content-Type" content="text/html;charset=utf-8"/>
+<meta name="ocr-system" content="pdfjuicer"/>
+<meta name="ocr-capabilities" content="ocr_page ocr_line ocrx_word"/>
+</head>
+<body>
+`)
+	fmt.Fprintf(&sb, "<div class=\"ocr_page\" id=\"page_%d\" title=\"image &quot;%s&quot;; bbox 0 0 %d %d; ppageno %d\">\n",
+		num+1, html.EscapeString(imageFName), bounds.Dx(), bounds.Dy(), num)
+	for i, line := range lines {
+		fmt.Fprintf(&sb, "<span class=\"ocr_line\" id=\"line_%d_%d\" title=\"%s\">", num+1, i+1, hocrBBox(line.rect()))
+		for j, word := range line.Words {
+			if j > 0 {
+				sb.WriteString(" ")
+			}
+			fmt.Fprintf(&sb, "<span class=\"ocrx_word\" id=\"word_%d_%d_%d\" title=\"%s\">%s</span>",
+				num+1, i+1, j+1, hocrBBox(word.rect()), html.EscapeString(word.Text))
+		}
+		sb.WriteString("</span>\n")
+	}
+	sb.WriteString("</div>\n</body>\n</html>\n")
+	return sb.String()
+}
+
+// hocrBBox formats rectangle as hOCR bbox property
+func hocrBBox(r image.Rectangle) string {
+	return fmt.Sprintf("bbox %d %d %d %d", r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// alto formats lines as ALTO v4 document with a single text block
+func alto(num int, imageFName string, bounds image.Rectangle, lines []wordBox) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<alto xmlns="http://www.loc.gov/standards/alto/ns-v4#">
+<Description>
+<MeasurementUnit>pixel</MeasurementUnit>
+`)
+	fmt.Fprintf(&sb, "<sourceImageInformation><fileName>%s</fileName></sourceImageInformation>\n", html.EscapeString(imageFName))
+	sb.WriteString("</Description>\n<Layout>\n")
+	fmt.Fprintf(&sb, "<Page ID=\"page_%d\" PHYSICAL_IMG_NR=\"%d\" WIDTH=\"%d\" HEIGHT=\"%d\">\n",
+		num+1, num+1, bounds.Dx(), bounds.Dy())
+	fmt.Fprintf(&sb, "<PrintSpace HPOS=\"0\" VPOS=\"0\" WIDTH=\"%d\" HEIGHT=\"%d\">\n", bounds.Dx(), bounds.Dy())
+	if len(lines) > 0 {
+		var blockRect image.Rectangle
+		for _, line := range lines {
+			blockRect = blockRect.Union(line.rect())
+		}
+		fmt.Fprintf(&sb, "<TextBlock ID=\"block_%d\" %s>\n", num+1, altoPos(blockRect))
+		for i, line := range lines {
+			fmt.Fprintf(&sb, "<TextLine ID=\"line_%d_%d\" %s>\n", num+1, i+1, altoPos(line.rect()))
+			for j, word := range line.Words {
+				if j > 0 {
+					sb.WriteString("<SP/>\n")
+				}
+				fmt.Fprintf(&sb, "<String ID=\"word_%d_%d_%d\" CONTENT=\"%s\" %s/>\n",
+					num+1, i+1, j+1, html.EscapeString(word.Text), altoPos(word.rect()))
+			}
+			sb.WriteString("</TextLine>\n")
+		}
+		sb.WriteString("</TextBlock>\n")
+	}
+	sb.WriteString("</PrintSpace>\n</Page>\n</Layout>\n</alto>\n")
+	return sb.String()
+}
+
+// altoPos formats rectangle as ALTO position attributes
+func altoPos(r image.Rectangle) string {
+	return fmt.Sprintf("HPOS=\"%d\" VPOS=\"%d\" WIDTH=\"%d\" HEIGHT=\"%d\"", r.Min.X, r.Min.Y, r.Dx(), r.Dy())
+}
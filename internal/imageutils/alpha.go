@@ -0,0 +1,95 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+)
+
+// backgroundTolerance is how far from pure white a pixel of page background may be
+const backgroundTolerance = 8
+
+// WhiteToAlpha turns white page background into transparency. Background is white area
+// connected to image edges, white text, highlights and light areas enclosed by ink stay opaque.
+// Background and pixels bordering it get the least alpha which reproduces the original color
+// when composited over white, so antialiased edges of text keep their look on any background
+func WhiteToAlpha(img *image.RGBA) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	background := backgroundMask(img)
+	isBackground := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < w && y < h && background[y*w+x]
+	}
+
+	dstImg := image.NewRGBA(b)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*img.Stride + x*4
+			j := y*dstImg.Stride + x*4
+			r, g, bl, a := img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3]
+			if !isBackground(x, y) && !isBackground(x-1, y) && !isBackground(x+1, y) &&
+				!isBackground(x, y-1) && !isBackground(x, y+1) {
+				dstImg.Pix[j], dstImg.Pix[j+1], dstImg.Pix[j+2], dstImg.Pix[j+3] = r, g, bl, a
+				continue
+			}
+			m := min(r, g, bl)
+			// image.RGBA stores premultiplied colors: c - (1-a)*255 reduces to c - m
+			dstImg.Pix[j] = r - m
+			dstImg.Pix[j+1] = g - m
+			dstImg.Pix[j+2] = bl - m
+			dstImg.Pix[j+3] = 255 - m
+		}
+	}
+	return dstImg
+}
+
+// backgroundMask flood fills near white pixels starting from image edges,
+// mask is indexed by y*width+x relative to image bounds
+func backgroundMask(img *image.RGBA) []bool {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	mask := make([]bool, w*h)
+	isWhite := func(x, y int) bool {
+		i := y*img.Stride + x*4
+		return min(img.Pix[i], img.Pix[i+1], img.Pix[i+2]) >= 255-backgroundTolerance
+	}
+
+	var queue []int
+	visit := func(x, y int) {
+		if x < 0 || y < 0 || x >= w || y >= h || mask[y*w+x] || !isWhite(x, y) {
+			return
+		}
+		mask[y*w+x] = true
+		queue = append(queue, y*w+x)
+	}
+	for x := 0; x < w; x++ {
+		visit(x, 0)
+		visit(x, h-1)
+	}
+	for y := 0; y < h; y++ {
+		visit(0, y)
+		visit(w-1, y)
+	}
+	for len(queue) > 0 {
+		p := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		x, y := p%w, p/w
+		visit(x-1, y)
+		visit(x+1, y)
+		visit(x, y-1)
+		visit(x, y+1)
+	}
+	return mask
+}
+
+// Flatten composites image with transparency over a solid background color
+func Flatten(img *image.RGBA, bg color.RGBA) *image.RGBA {
+	dstImg := image.NewRGBA(img.Bounds())
+	for i := 0; i < len(img.Pix); i += 4 {
+		transparency := 255 - uint32(img.Pix[i+3])
+		dstImg.Pix[i] = img.Pix[i] + uint8(transparency*uint32(bg.R)/255)
+		dstImg.Pix[i+1] = img.Pix[i+1] + uint8(transparency*uint32(bg.G)/255)
+		dstImg.Pix[i+2] = img.Pix[i+2] + uint8(transparency*uint32(bg.B)/255)
+		dstImg.Pix[i+3] = 255
+	}
+	return dstImg
+}
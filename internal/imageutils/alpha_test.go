@@ -0,0 +1,79 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type whiteToAlphaTestCase struct {
+	comment string
+	// frame is drawn around the center pixel of white page when set
+	frame       *color.RGBA
+	inputValue  color.RGBA
+	expectedVal color.RGBA
+}
+
+var black = color.RGBA{A: 255}
+
+var WhiteToAlphaTestCase = []whiteToAlphaTestCase{
+	{
+		comment:     "Pure white background becomes transparent",
+		inputValue:  color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		expectedVal: color.RGBA{},
+	},
+	{
+		comment:     "Mid-grey edge becomes half transparent black",
+		inputValue:  color.RGBA{R: 128, G: 128, B: 128, A: 255},
+		expectedVal: color.RGBA{A: 127},
+	},
+	{
+		comment:     "Colored pixel keeps its color over white",
+		inputValue:  color.RGBA{R: 200, G: 100, B: 50, A: 255},
+		expectedVal: color.RGBA{R: 150, G: 50, A: 205},
+	},
+	{
+		comment:     "Saturated color stays opaque",
+		inputValue:  color.RGBA{R: 255, A: 255},
+		expectedVal: color.RGBA{R: 255, A: 255},
+	},
+	{
+		comment:     "White enclosed by ink stays opaque",
+		frame:       &black,
+		inputValue:  color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		expectedVal: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	},
+	{
+		comment:     "Grey enclosed by ink stays opaque",
+		frame:       &black,
+		inputValue:  color.RGBA{R: 128, G: 128, B: 128, A: 255},
+		expectedVal: color.RGBA{R: 128, G: 128, B: 128, A: 255},
+	},
+}
+
+func TestWhiteToAlpha(t *testing.T) {
+	for _, tc := range WhiteToAlphaTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 7, 7))
+			for i := range img.Pix {
+				img.Pix[i] = 255
+			}
+			if tc.frame != nil {
+				for y := 2; y <= 4; y++ {
+					for x := 2; x <= 4; x++ {
+						img.SetRGBA(x, y, *tc.frame)
+					}
+				}
+			}
+			img.SetRGBA(3, 3, tc.inputValue)
+
+			dstImg := WhiteToAlpha(img)
+			if got := dstImg.RGBAAt(3, 3); got != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+			if got := dstImg.RGBAAt(0, 0); got != (color.RGBA{}) {
+				t.Errorf("%s test. want transparent corner, got: %v", tc.comment, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package imageutils
+
+import (
+	"image"
+	"strings"
+)
+
+// Anchor is position of an element inside an image, like center, top or bottom-right
+type Anchor string
+
+// Tile is a position repeating an element over the whole image instead of placing it once
+const Tile Anchor = "tile"
+
+// Place returns top left corner of an element of a given size placed at anchor
+// inside outer rectangle, keeping margin from the edges
+func (a Anchor) Place(outer image.Rectangle, size image.Point, margin int) image.Point {
+	pos := image.Pt(outer.Min.X+(outer.Dx()-size.X)/2, outer.Min.Y+(outer.Dy()-size.Y)/2)
+
+	anchor := string(a)
+	if strings.Contains(anchor, "left") {
+		pos.X = outer.Min.X + margin
+	} else if strings.Contains(anchor, "right") {
+		pos.X = outer.Max.X - margin - size.X
+	}
+	if strings.Contains(anchor, "top") {
+		pos.Y = outer.Min.Y + margin
+	} else if strings.Contains(anchor, "bottom") {
+		pos.Y = outer.Max.Y - margin - size.Y
+	}
+	return pos
+}
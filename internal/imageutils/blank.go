@@ -0,0 +1,46 @@
+package imageutils
+
+import (
+	"image"
+	"math"
+)
+
+// inkContrast is luminance difference from paper color which is treated as ink
+const inkContrast = 64
+
+// InkStats returns percent of ink pixels and standard deviation of luminance.
+// Paper color is the median luminance, so gray scans of blank sheets have no ink
+func InkStats(img *image.RGBA) (coverage, stdDev float64) {
+	var histogram [256]int
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			off := img.PixOffset(x, y)
+			histogram[(299*int(img.Pix[off])+587*int(img.Pix[off+1])+114*int(img.Pix[off+2]))/1000]++
+		}
+	}
+	total := b.Dx() * b.Dy()
+	if total == 0 {
+		return 0, 0
+	}
+
+	paper, seen := 0, 0
+	for luma, count := range histogram {
+		seen += count
+		if seen*2 >= total {
+			paper = luma
+			break
+		}
+	}
+
+	var ink, sum, sumSq float64
+	for luma, count := range histogram {
+		if luma < paper-inkContrast || luma > paper+inkContrast {
+			ink += float64(count)
+		}
+		sum += float64(luma * count)
+		sumSq += float64(luma * luma * count)
+	}
+	mean := sum / float64(total)
+	return ink * 100 / float64(total), math.Sqrt(max(sumSq/float64(total)-mean*mean, 0))
+}
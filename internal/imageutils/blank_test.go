@@ -0,0 +1,59 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+type inkStatsTestCase struct {
+	comment  string
+	paper    uint8
+	ink      uint8
+	inkEvery int
+	coverage float64
+}
+
+var InkStatsTestCase = []inkStatsTestCase{
+	{
+		comment:  "Blank white page",
+		paper:    255,
+		ink:      255,
+		inkEvery: 1,
+		coverage: 0,
+	},
+	{
+		comment:  "Gray scan with text",
+		paper:    200,
+		ink:      20,
+		inkEvery: 10,
+		coverage: 10,
+	},
+	{
+		comment:  "Faint marks are not ink",
+		paper:    250,
+		ink:      230,
+		inkEvery: 4,
+		coverage: 0,
+	},
+}
+
+func TestInkStats(t *testing.T) {
+	for _, tc := range InkStatsTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 50, 20))
+			for i := 0; i < 50*20; i++ {
+				c := tc.paper
+				if i%tc.inkEvery == 0 {
+					c = tc.ink
+				}
+				img.SetRGBA(i%50, i/50, color.RGBA{R: c, G: c, B: c, A: 255})
+			}
+			coverage, _ := InkStats(img)
+			if math.Abs(coverage-tc.coverage) > 0.01 {
+				t.Errorf("%s test. want coverage %.2f, got: %.2f", tc.comment, tc.coverage, coverage)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/draw"
+)
+
+// BilevelPalette is black and white palette of bilevel images, encoders write it with 1 bit per pixel
+var BilevelPalette = color.Palette{color.Black, color.White}
+
+// Grayscale converts image to 8-bit grayscale
+func Grayscale(img image.Image) *image.Gray {
+	gray := image.NewGray(img.Bounds())
+	draw.Draw(gray, gray.Bounds(), img, img.Bounds().Min, draw.Src)
+	return gray
+}
+
+// Threshold converts grayscale image to bilevel, pixels brighter than threshold become white
+func Threshold(gray *image.Gray, threshold int) *image.Paletted {
+	bilevel := image.NewPaletted(gray.Bounds(), BilevelPalette)
+	for i, v := range gray.Pix {
+		if int(v) > threshold {
+			bilevel.Pix[i] = 1
+		}
+	}
+	return bilevel
+}
+
+// OtsuThreshold finds threshold which best separates image histogram into two classes
+func OtsuThreshold(gray *image.Gray) int {
+	var histogram [256]int
+	for _, v := range gray.Pix {
+		histogram[v]++
+	}
+
+	total := len(gray.Pix)
+	sumAll := 0
+	for i, count := range histogram {
+		sumAll += i * count
+	}
+
+	var best int
+	var bestVariance float64
+	weightBg, sumBg := 0, 0
+	for i := 0; i < 256; i++ {
+		weightBg += histogram[i]
+		if weightBg == 0 {
+			continue
+		}
+		weightFg := total - weightBg
+		if weightFg == 0 {
+			break
+		}
+		sumBg += i * histogram[i]
+
+		meanBg := float64(sumBg) / float64(weightBg)
+		meanFg := float64(sumAll-sumBg) / float64(weightFg)
+		variance := float64(weightBg) * float64(weightFg) * (meanBg - meanFg) * (meanBg - meanFg)
+		if variance > bestVariance {
+			bestVariance = variance
+			best = i
+		}
+	}
+	return best
+}
+
+// Dither converts grayscale image to bilevel with Floyd–Steinberg error diffusion
+func Dither(gray *image.Gray) *image.Paletted {
+	b := gray.Bounds()
+	width, height := b.Dx(), b.Dy()
+	bilevel := image.NewPaletted(b, BilevelPalette)
+
+	// current and next row errors, padded by one pixel on both sides
+	curr := make([]float64, width+2)
+	next := make([]float64, width+2)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := float64(gray.Pix[y*gray.Stride+x]) + curr[x+1]
+			out := 0.0
+			if v > 127 {
+				out = 255
+				bilevel.Pix[y*bilevel.Stride+x] = 1
+			}
+			quantErr := v - out
+			curr[x+2] += quantErr * 7 / 16
+			next[x] += quantErr * 3 / 16
+			next[x+1] += quantErr * 5 / 16
+			next[x+2] += quantErr * 1 / 16
+		}
+		curr, next = next, curr
+		clear(next)
+	}
+	return bilevel
+}
@@ -0,0 +1,43 @@
+package imageutils
+
+import (
+	"image"
+	"testing"
+)
+
+type otsuTestCase struct {
+	comment string
+	dark    uint8
+	light   uint8
+}
+
+var OtsuTestCase = []otsuTestCase{
+	{
+		comment: "Black text on white",
+		dark:    0,
+		light:   255,
+	},
+	{
+		comment: "Gray text on off-white scan",
+		dark:    90,
+		light:   220,
+	},
+}
+
+func TestOtsuThreshold(t *testing.T) {
+	for _, tc := range OtsuTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			gray := image.NewGray(image.Rect(0, 0, 10, 10))
+			for i := range gray.Pix {
+				gray.Pix[i] = tc.light
+				if i%4 == 0 {
+					gray.Pix[i] = tc.dark
+				}
+			}
+			got := OtsuThreshold(gray)
+			if got < int(tc.dark) || got >= int(tc.light) {
+				t.Errorf("%s test. want threshold in [%d, %d), got: %d", tc.comment, tc.dark, tc.light, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+)
+
+// diffFade is share of white mixed into unchanged pixels of highlighted image
+const diffFade = 0.75
+
+// DiffMask compares images pixel by pixel anchored at their top left corners. Pixels where
+// any channel differs by more than threshold are white in the mask, the area covered by
+// only one of the images counts as changed. Number of changed pixels is returned with the mask
+func DiffMask(a, b *image.RGBA, threshold int) (*image.Gray, int) {
+	sizeA, sizeB := a.Bounds().Size(), b.Bounds().Size()
+	mask := image.NewGray(image.Rect(0, 0, max(sizeA.X, sizeB.X), max(sizeA.Y, sizeB.Y)))
+	common := image.Rectangle{Max: image.Pt(min(sizeA.X, sizeB.X), min(sizeA.Y, sizeB.Y))}
+
+	changed := 0
+	for y := 0; y < mask.Rect.Dy(); y++ {
+		for x := 0; x < mask.Rect.Dx(); x++ {
+			p := image.Pt(x, y)
+			if p.In(common) {
+				offA := a.PixOffset(a.Rect.Min.X+x, a.Rect.Min.Y+y)
+				offB := b.PixOffset(b.Rect.Min.X+x, b.Rect.Min.Y+y)
+				same := true
+				for c := 0; c < 4; c++ {
+					if d := int(a.Pix[offA+c]) - int(b.Pix[offB+c]); d > threshold || -d > threshold {
+						same = false
+						break
+					}
+				}
+				if same {
+					continue
+				}
+			} else if !p.In(image.Rectangle{Max: sizeA}) && !p.In(image.Rectangle{Max: sizeB}) {
+				// corner outside of both images
+				continue
+			}
+			mask.Pix[mask.PixOffset(x, y)] = 255
+			changed++
+		}
+	}
+	return mask, changed
+}
+
+// Highlight draws faded image with changed pixels of the mask painted in highlight color,
+// result has the size of the mask
+func Highlight(img *image.RGBA, mask *image.Gray, highlight color.RGBA) *image.RGBA {
+	dstImg := image.NewRGBA(mask.Rect)
+	size := img.Bounds().Size()
+	for y := 0; y < mask.Rect.Dy(); y++ {
+		for x := 0; x < mask.Rect.Dx(); x++ {
+			off := dstImg.PixOffset(x, y)
+			if mask.Pix[mask.PixOffset(x, y)] > 0 {
+				copy(dstImg.Pix[off:off+4], []uint8{highlight.R, highlight.G, highlight.B, 255})
+				continue
+			}
+			if x >= size.X || y >= size.Y {
+				copy(dstImg.Pix[off:off+4], []uint8{255, 255, 255, 255})
+				continue
+			}
+			src := img.Pix[img.PixOffset(img.Rect.Min.X+x, img.Rect.Min.Y+y):]
+			for c := 0; c < 3; c++ {
+				dstImg.Pix[off+c] = uint8(float64(src[c])*(1-diffFade) + 255*diffFade)
+			}
+			dstImg.Pix[off+3] = 255
+		}
+	}
+	return dstImg
+}
@@ -0,0 +1,75 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type diffMaskTestCase struct {
+	comment   string
+	sizeB     image.Point
+	mark      image.Rectangle
+	threshold int
+	changed   int
+}
+
+var DiffMaskTestCase = []diffMaskTestCase{
+	{
+		comment:   "Identical pages",
+		sizeB:     image.Pt(20, 10),
+		threshold: 32,
+		changed:   0,
+	},
+	{
+		comment:   "Changed region",
+		sizeB:     image.Pt(20, 10),
+		mark:      image.Rect(2, 2, 5, 4),
+		threshold: 32,
+		changed:   6,
+	},
+	{
+		comment:   "Change below threshold",
+		sizeB:     image.Pt(20, 10),
+		mark:      image.Rect(2, 2, 5, 4),
+		threshold: 128,
+		changed:   0,
+	},
+	{
+		comment:   "Longer page",
+		sizeB:     image.Pt(20, 12),
+		threshold: 32,
+		changed:   40,
+	},
+}
+
+func TestDiffMask(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	for _, tc := range DiffMaskTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			a := image.NewRGBA(image.Rect(0, 0, 20, 10))
+			// b is placed off origin to check that images are compared from their corners
+			b := image.NewRGBA(image.Rectangle{Min: image.Pt(5, 5), Max: tc.sizeB.Add(image.Pt(5, 5))})
+			for _, img := range []*image.RGBA{a, b} {
+				for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+					for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+						img.SetRGBA(x, y, white)
+					}
+				}
+			}
+			for y := tc.mark.Min.Y; y < tc.mark.Max.Y; y++ {
+				for x := tc.mark.Min.X; x < tc.mark.Max.X; x++ {
+					b.SetRGBA(x+5, y+5, color.RGBA{R: 155, G: 155, B: 155, A: 255})
+				}
+			}
+
+			mask, changed := DiffMask(a, b, tc.threshold)
+			if changed != tc.changed {
+				t.Errorf("%s test. want %d changed pixels, got: %d", tc.comment, tc.changed, changed)
+			}
+			if mask.Bounds().Size() != tc.sizeB {
+				t.Errorf("%s test. want mask size %v, got: %v", tc.comment, tc.sizeB, mask.Bounds().Size())
+			}
+		})
+	}
+}
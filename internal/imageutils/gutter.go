@@ -0,0 +1,75 @@
+package imageutils
+
+import (
+	"image"
+)
+
+const (
+	// gutter is searched in the middle part of the spread, from 40% to 60%
+	gutterBandStart = 0.4
+	gutterBandEnd   = 0.6
+	// darkness (0-255) of a pixel treated as ink
+	gutterInk = 64
+	// share of ink pixels (percent) in a line which is treated as binding shadow or fold
+	gutterShadow = 90
+	// share of ink pixels (percent) above the lightest line which is still treated as blank
+	gutterBlankTolerance = 1
+)
+
+// Gutter finds position of the gutter between two pages of a scanned spread.
+// For vertical cut it returns x coordinate, for horizontal cut y coordinate.
+// Dark binding shadow is preferred, otherwise the middle of the widest blank gap is used
+func Gutter(img *image.RGBA, vertical bool) int {
+	b := img.Bounds()
+	length, cross, offset := b.Dx(), b.Dy(), b.Min.X
+	if !vertical {
+		length, cross, offset = b.Dy(), b.Dx(), b.Min.Y
+	}
+	start := int(float64(length) * gutterBandStart)
+	end := max(int(float64(length)*gutterBandEnd), start+1)
+
+	// coverage is percent of ink pixels in every line of the band
+	coverage := make([]int, end-start)
+	for i := range coverage {
+		ink, count := 0, 0
+		for j := 0; j < cross; j += 2 {
+			x, y := b.Min.X+start+i, b.Min.Y+j
+			if !vertical {
+				x, y = b.Min.X+j, b.Min.Y+start+i
+			}
+			off := img.PixOffset(x, y)
+			if 255-(299*int(img.Pix[off])+587*int(img.Pix[off+1])+114*int(img.Pix[off+2]))/1000 > gutterInk {
+				ink++
+			}
+			count++
+		}
+		coverage[i] = ink * 100 / max(count, 1)
+	}
+
+	darkest, lightest := 0, 0
+	for i, c := range coverage {
+		if c > coverage[darkest] {
+			darkest = i
+		}
+		if c < coverage[lightest] {
+			lightest = i
+		}
+	}
+	if coverage[darkest] >= gutterShadow {
+		return offset + start + darkest
+	}
+
+	bestStart, bestLen, runStart := 0, 0, -1
+	for i := 0; i <= len(coverage); i++ {
+		blank := i < len(coverage) && coverage[i] <= coverage[lightest]+gutterBlankTolerance
+		if blank && runStart == -1 {
+			runStart = i
+		} else if !blank && runStart != -1 {
+			if i-runStart > bestLen {
+				bestStart, bestLen = runStart, i-runStart
+			}
+			runStart = -1
+		}
+	}
+	return offset + start + bestStart + bestLen/2
+}
@@ -0,0 +1,64 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type gutterTestCase struct {
+	comment string
+	// columns filled with dark color, half-open ranges
+	dark   [][2]int
+	shade  uint8
+	minPos int
+	maxPos int
+}
+
+var GutterTestCase = []gutterTestCase{
+	{
+		comment: "Blank gap off center",
+		dark:    [][2]int{{10, 52}, {58, 90}},
+		shade:   0,
+		minPos:  52,
+		maxPos:  58,
+	},
+	{
+		comment: "Binding shadow",
+		dark:    [][2]int{{10, 40}, {45, 47}, {60, 90}},
+		shade:   60,
+		minPos:  45,
+		maxPos:  47,
+	},
+	{
+		comment: "Blank spread",
+		dark:    nil,
+		shade:   0,
+		minPos:  48,
+		maxPos:  52,
+	},
+}
+
+func TestGutter(t *testing.T) {
+	for _, tc := range GutterTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 100, 40))
+			for x := 0; x < 100; x++ {
+				for y := 0; y < 40; y++ {
+					c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+					for _, r := range tc.dark {
+						// text lines on pages, shadow covers the whole height
+						if x >= r[0] && x < r[1] && (y%4 == 0 || r[1]-r[0] < 5) {
+							c = color.RGBA{R: tc.shade, G: tc.shade, B: tc.shade, A: 255}
+						}
+					}
+					img.SetRGBA(x, y, c)
+				}
+			}
+			got := Gutter(img, true)
+			if got < tc.minPos || got >= tc.maxPos {
+				t.Errorf("%s test. want gutter in [%d, %d), got: %d", tc.comment, tc.minPos, tc.maxPos, got)
+			}
+		})
+	}
+}
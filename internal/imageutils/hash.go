@@ -0,0 +1,113 @@
+package imageutils
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// AHash is average hash: 8x8 brightness bits set where cell is brighter than the mean
+func AHash(img *image.RGBA) uint64 {
+	grid := grayGrid(img, 8, 8)
+	mean := 0.0
+	for _, v := range grid {
+		mean += v
+	}
+	mean /= float64(len(grid))
+
+	var hash uint64
+	for i, v := range grid {
+		if v > mean {
+			hash |= 1 << i
+		}
+	}
+	return hash
+}
+
+// DHash is difference hash: bits set where cell is brighter than its right neighbor
+func DHash(img *image.RGBA) uint64 {
+	grid := grayGrid(img, 9, 8)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if grid[y*9+x] > grid[y*9+x+1] {
+				hash |= 1 << (y*8 + x)
+			}
+		}
+	}
+	return hash
+}
+
+// PHash is perceptual hash: bits of 8x8 lowest frequencies of 32x32 DCT set where
+// coefficient is above their median, it is the most robust to small changes
+func PHash(img *image.RGBA) uint64 {
+	const size, low = 32, 8
+	grid := grayGrid(img, size, size)
+
+	// separable 2D DCT-II, only low frequencies are needed
+	var cosines [low][size]float64
+	for u := 0; u < low; u++ {
+		for x := 0; x < size; x++ {
+			cosines[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * size))
+		}
+	}
+	var rows [size][low]float64
+	for y := 0; y < size; y++ {
+		for u := 0; u < low; u++ {
+			for x := 0; x < size; x++ {
+				rows[y][u] += grid[y*size+x] * cosines[u][x]
+			}
+		}
+	}
+	coefs := make([]float64, 0, low*low)
+	for v := 0; v < low; v++ {
+		for u := 0; u < low; u++ {
+			sum := 0.0
+			for y := 0; y < size; y++ {
+				sum += rows[y][u] * cosines[v][y]
+			}
+			coefs = append(coefs, sum)
+		}
+	}
+
+	// DC coefficient is average brightness, it is left out of the median
+	sorted := append([]float64(nil), coefs[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coefs {
+		if c > median {
+			hash |= 1 << i
+		}
+	}
+	return hash
+}
+
+// Hamming returns number of different bits of two hashes
+func Hamming(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// grayGrid averages luminance of image over w x h cells, row by row
+func grayGrid(img *image.RGBA, w, h int) []float64 {
+	b := img.Bounds()
+	sums := make([]float64, w*h)
+	counts := make([]int, w*h)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		cy := (y - b.Min.Y) * h / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cell := cy*w + (x-b.Min.X)*w/b.Dx()
+			off := img.PixOffset(x, y)
+			sums[cell] += float64(299*int(img.Pix[off])+587*int(img.Pix[off+1])+114*int(img.Pix[off+2])) / 1000
+			counts[cell]++
+		}
+	}
+	for i := range sums {
+		if counts[i] > 0 {
+			sums[i] /= float64(counts[i])
+		}
+	}
+	return sums
+}
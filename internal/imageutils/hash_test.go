@@ -0,0 +1,63 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type hashTestCase struct {
+	comment string
+	hash    func(img *image.RGBA) uint64
+}
+
+var HashTestCase = []hashTestCase{
+	{
+		comment: "Average hash",
+		hash:    AHash,
+	},
+	{
+		comment: "Difference hash",
+		hash:    DHash,
+	},
+	{
+		comment: "Perceptual hash",
+		hash:    PHash,
+	},
+}
+
+// slide draws white page with dark bars, one bar per bullet
+func slide(width, height, bullets int, dot bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			row := y * 8 / height
+			if row < bullets && x > width/10 && x < width*(6+row)/10 && y%(height/8) < height/16 {
+				c = color.RGBA{R: 20, G: 20, B: 60, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	if dot {
+		img.SetRGBA(width-2, height-2, color.RGBA{A: 255})
+	}
+	return img
+}
+
+func TestHash(t *testing.T) {
+	for _, tc := range HashTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			page := tc.hash(slide(400, 300, 4, false))
+			if d := Hamming(page, tc.hash(slide(200, 150, 4, false))); d > 4 {
+				t.Errorf("%s test. resized page differs by %d bits", tc.comment, d)
+			}
+			if d := Hamming(page, tc.hash(slide(400, 300, 4, true))); d > 2 {
+				t.Errorf("%s test. page with a speck differs by %d bits", tc.comment, d)
+			}
+			if d := Hamming(page, tc.hash(slide(400, 300, 1, false))); d < 5 {
+				t.Errorf("%s test. different page differs by %d bits only", tc.comment, d)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package imageutils
+
+import (
+	"image"
+	// decoders for images loaded from disk
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+
+	"golang.org/x/image/draw"
+)
+
+// Load decodes png or jpeg image from file into RGBA image
+func Load(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba, nil
+}
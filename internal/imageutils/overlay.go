@@ -0,0 +1,74 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+// Clone copies image, so it can be drawn on without changing the original
+func Clone(img *image.RGBA) *image.RGBA {
+	dstImg := image.NewRGBA(img.Bounds())
+	copy(dstImg.Pix, img.Pix)
+	return dstImg
+}
+
+// Overlay draws overlay onto dst at a given point with opacity from 0 to 1
+func Overlay(dst *image.RGBA, overlay image.Image, pos image.Point, opacity float64) {
+	mask := image.NewUniform(color.Alpha{A: uint8(math.Round(clamp01(opacity) * 255))})
+	rect := overlay.Bounds().Sub(overlay.Bounds().Min).Add(pos)
+	draw.DrawMask(dst, rect, overlay, overlay.Bounds().Min, mask, image.Point{}, draw.Over)
+}
+
+// OverlayTiled repeats overlay over dst in rows and columns starting from its top left corner,
+// neighbouring copies are separated by gap, opacity is from 0 to 1
+func OverlayTiled(dst *image.RGBA, overlay image.Image, gap image.Point, opacity float64) {
+	b := dst.Bounds()
+	step := overlay.Bounds().Size().Add(gap)
+	if step.X <= 0 || step.Y <= 0 {
+		return
+	}
+	for y := b.Min.Y; y < b.Max.Y; y += step.Y {
+		for x := b.Min.X; x < b.Max.X; x += step.X {
+			Overlay(dst, overlay, image.Pt(x, y), opacity)
+		}
+	}
+}
+
+// Rotate rotates image counterclockwise by angle in degrees around its center.
+// Canvas is enlarged to fit the rotated image, uncovered corners stay transparent
+func Rotate(img *image.RGBA, degrees float64) *image.RGBA {
+	b := img.Bounds()
+	sin, cos := math.Sincos(-degrees * math.Pi / 180)
+	width, height := float64(b.Dx()), float64(b.Dy())
+	dstWidth := math.Ceil(math.Abs(width*cos) + math.Abs(height*sin))
+	dstHeight := math.Ceil(math.Abs(width*sin) + math.Abs(height*cos))
+	dstImg := image.NewRGBA(image.Rect(0, 0, int(dstWidth), int(dstHeight)))
+
+	// maps source point to destination: move source center to origin, rotate, move to destination center
+	srcCX, srcCY := float64(b.Min.X)+width/2, float64(b.Min.Y)+height/2
+	dstCX, dstCY := dstWidth/2, dstHeight/2
+	m := f64.Aff3{
+		cos, -sin, dstCX - cos*srcCX + sin*srcCY,
+		sin, cos, dstCY - sin*srcCX - cos*srcCY,
+	}
+	draw.BiLinear.Transform(dstImg, m, img, b, draw.Src, nil)
+	return dstImg
+}
+
+// clamp01 limits value to 0..1 range
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// Pad extends image canvas by given number of pixels on each side filling new area with color
+func Pad(img *image.RGBA, top, right, bottom, left int, bg color.Color) *image.RGBA {
+	b := img.Bounds()
+	dstImg := image.NewRGBA(image.Rect(0, 0, b.Dx()+left+right, b.Dy()+top+bottom))
+	draw.Draw(dstImg, dstImg.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	draw.Draw(dstImg, b.Sub(b.Min).Add(image.Pt(left, top)), img, b.Min, draw.Src)
+	return dstImg
+}
@@ -0,0 +1,87 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// quantizeSamples limits number of pixels used to build palette of large images
+const quantizeSamples = 1 << 16
+
+// MedianCut builds palette of up to n colors for opaque image: the box of colors
+// with the widest channel range is split at its median until there are n boxes
+func MedianCut(img *image.RGBA, n int) color.Palette {
+	bounds := img.Bounds()
+	step := max(bounds.Dx()*bounds.Dy()/quantizeSamples, 1)
+
+	var pixels [][3]uint8
+	i := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if i%step == 0 {
+				off := img.PixOffset(x, y)
+				pixels = append(pixels, [3]uint8{img.Pix[off], img.Pix[off+1], img.Pix[off+2]})
+			}
+			i++
+		}
+	}
+
+	boxes := [][][3]uint8{pixels}
+	for len(boxes) < n {
+		widest, channel, widestRange := -1, 0, 0
+		for b, box := range boxes {
+			for c := 0; c < 3; c++ {
+				lo, hi := channelRange(box, c)
+				if hi-lo > widestRange {
+					widest, channel, widestRange = b, c, hi-lo
+				}
+			}
+		}
+		// remaining boxes hold a single color each
+		if widest == -1 {
+			break
+		}
+		box := boxes[widest]
+		sort.Slice(box, func(i, j int) bool { return box[i][channel] < box[j][channel] })
+		median := len(box) / 2
+		// keep equal colors in one box so the split always separates distinct colors
+		for median > 1 && box[median-1][channel] == box[median][channel] {
+			median--
+		}
+		if box[median-1][channel] == box[median][channel] {
+			for median < len(box)-1 && box[median-1][channel] == box[median][channel] {
+				median++
+			}
+		}
+		boxes[widest] = box[:median]
+		boxes = append(boxes, box[median:])
+	}
+
+	palette := make(color.Palette, 0, len(boxes))
+	for _, box := range boxes {
+		var sum [3]int
+		for _, p := range box {
+			for c := 0; c < 3; c++ {
+				sum[c] += int(p[c])
+			}
+		}
+		palette = append(palette, color.RGBA{
+			R: uint8(sum[0] / len(box)),
+			G: uint8(sum[1] / len(box)),
+			B: uint8(sum[2] / len(box)),
+			A: 0xff,
+		})
+	}
+	return palette
+}
+
+// channelRange returns minimal and maximal values of a color channel in a box
+func channelRange(box [][3]uint8, c int) (int, int) {
+	lo, hi := 255, 0
+	for _, p := range box {
+		lo = min(lo, int(p[c]))
+		hi = max(hi, int(p[c]))
+	}
+	return lo, hi
+}
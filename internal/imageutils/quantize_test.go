@@ -0,0 +1,57 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type medianCutTestCase struct {
+	comment  string
+	colors   int
+	expected int
+}
+
+var MedianCutTestCase = []medianCutTestCase{
+	{
+		comment:  "Palette larger than image colors",
+		colors:   8,
+		expected: 3,
+	},
+	{
+		comment:  "Palette smaller than image colors",
+		colors:   2,
+		expected: 2,
+	},
+}
+
+func TestMedianCut(t *testing.T) {
+	imageColors := []color.RGBA{
+		{R: 255, G: 255, B: 255, A: 255},
+		{R: 200, G: 30, B: 30, A: 255},
+		{R: 0, G: 0, B: 0, A: 255},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 30, 10))
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 10; y++ {
+			img.SetRGBA(x, y, imageColors[x/10])
+		}
+	}
+
+	for _, tc := range MedianCutTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			palette := MedianCut(img, tc.colors)
+			if len(palette) != tc.expected {
+				t.Fatalf("%s test. want %d colors, got: %d", tc.comment, tc.expected, len(palette))
+			}
+			if tc.colors < len(imageColors) {
+				return
+			}
+			for _, c := range imageColors {
+				if palette.Convert(c) != color.Color(c) {
+					t.Errorf("%s test. color %v is missing in palette %v", tc.comment, c, palette)
+				}
+			}
+		})
+	}
+}
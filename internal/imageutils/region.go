@@ -0,0 +1,49 @@
+package imageutils
+
+import (
+	"image"
+	"math"
+)
+
+// Unit of a region coordinate
+type Unit string
+
+const (
+	// Points are PDF page coordinates, 1/72 of an inch
+	Points Unit = "pt"
+	// Pixels are coordinates of the rendered image
+	Pixels Unit = "px"
+	// Percent is a share of rendered page width or height
+	Percent Unit = "%"
+)
+
+// Length is a region coordinate in given units
+type Length struct {
+	Value float64
+	Unit  Unit
+}
+
+// Region is a rectangle on a page defined by its top left corner, width and height
+type Region struct {
+	X, Y, W, H Length
+}
+
+// Rect converts region into pixel rectangle of a page rendered with a given DPI
+func (r Region) Rect(bounds image.Rectangle, dpi float64) image.Rectangle {
+	x := r.X.pixels(bounds.Dx(), dpi)
+	y := r.Y.pixels(bounds.Dy(), dpi)
+	w := r.W.pixels(bounds.Dx(), dpi)
+	h := r.H.pixels(bounds.Dy(), dpi)
+	return image.Rect(x, y, x+w, y+h).Add(bounds.Min).Intersect(bounds)
+}
+
+// pixels converts length to pixels, total is page size along the same axis
+func (l Length) pixels(total int, dpi float64) int {
+	switch l.Unit {
+	case Percent:
+		return int(math.Round(l.Value * float64(total) / 100))
+	case Points:
+		return int(math.Round(l.Value * dpi / 72))
+	}
+	return int(math.Round(l.Value))
+}
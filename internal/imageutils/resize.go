@@ -23,9 +23,23 @@ func Resize(srcImg *image.RGBA, dstWidth, dstHeight int) *image.RGBA {
 		dstImg.Bounds(),
 		srcImg,
 		srcImg.Bounds(),
-		draw.Over,
+		draw.Src,
 		nil,
 	)
 	return dstImg
 
 }
+
+// Fit resizes input image to fit into maxWidth x maxHeight box keeping its aspect ratio
+func Fit(srcImg *image.RGBA, maxWidth, maxHeight int) *image.RGBA {
+	srcWidth, srcHeight := srcImg.Bounds().Dx(), srcImg.Bounds().Dy()
+	if srcWidth == 0 || srcHeight == 0 {
+		return srcImg
+	}
+
+	scale := min(float64(maxWidth)/float64(srcWidth), float64(maxHeight)/float64(srcHeight))
+	dstWidth := max(int(float64(srcWidth)*scale), 1)
+	dstHeight := max(int(float64(srcHeight)*scale), 1)
+
+	return Resize(srcImg, dstWidth, dstHeight)
+}
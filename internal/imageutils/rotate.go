@@ -0,0 +1,127 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// RotateRight rotates image clockwise by a number of quarter turns without resampling
+func RotateRight(img *image.RGBA, turns int) *image.RGBA {
+	turns = ((turns % 4) + 4) % 4
+	if turns == 0 {
+		return img
+	}
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	dstRect := image.Rect(0, 0, height, width)
+	if turns == 2 {
+		dstRect = image.Rect(0, 0, width, height)
+	}
+	dstImg := image.NewRGBA(dstRect)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch turns {
+			case 1:
+				dx, dy = height-1-y, x
+			case 2:
+				dx, dy = width-1-x, height-1-y
+			case 3:
+				dx, dy = y, width-1-x
+			}
+			src := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			dst := dstImg.PixOffset(dx, dy)
+			copy(dstImg.Pix[dst:dst+4], img.Pix[src:src+4])
+		}
+	}
+	return dstImg
+}
+
+// RotateRectRight maps rectangle of an image of given size to the image rotated clockwise
+// by a number of quarter turns, like RotateRight does with its pixels
+func RotateRectRight(rect image.Rectangle, size image.Point, turns int) image.Rectangle {
+	switch ((turns % 4) + 4) % 4 {
+	case 1:
+		return image.Rect(size.Y-rect.Max.Y, rect.Min.X, size.Y-rect.Min.Y, rect.Max.X)
+	case 2:
+		return image.Rect(size.X-rect.Max.X, size.Y-rect.Max.Y, size.X-rect.Min.X, size.Y-rect.Min.Y)
+	case 3:
+		return image.Rect(rect.Min.Y, size.X-rect.Max.X, rect.Max.Y, size.X-rect.Min.X)
+	}
+	return rect
+}
+
+const (
+	// skew is searched within ±maxSkew degrees, first coarsely then around the best coarse angle
+	maxSkew        = 5.0
+	skewCoarseStep = 0.25
+	skewFineStep   = 0.025
+	// smaller skew is not visible and is not worth resampling the page
+	skewMinAngle = 0.1
+	// pages are downscaled for skew estimation
+	skewSampleWidth = 1000
+)
+
+// SkewAngle estimates counterclockwise skew of text lines in degrees. Ink pixels are projected
+// onto the vertical axis along candidate angles, the angle giving the sharpest profile wins
+func SkewAngle(img *image.RGBA) float64 {
+	sample := img
+	if img.Bounds().Dx() > skewSampleWidth {
+		sample = Fit(img, skewSampleWidth, img.Bounds().Dy())
+	}
+	gray := Grayscale(sample)
+	threshold := OtsuThreshold(gray)
+
+	var points []image.Point
+	b := gray.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if int(gray.GrayAt(x, y).Y) < threshold {
+				points = append(points, image.Pt(x-b.Min.X, y-b.Min.Y))
+			}
+		}
+	}
+	if len(points) == 0 {
+		return 0
+	}
+
+	best := bestSkew(points, b.Dx(), b.Dy(), -maxSkew, maxSkew, skewCoarseStep)
+	best = bestSkew(points, b.Dx(), b.Dy(), best-skewCoarseStep, best+skewCoarseStep, skewFineStep)
+	if math.Abs(best) < skewMinAngle {
+		return 0
+	}
+	return best
+}
+
+// bestSkew returns angle in range with the largest sum of squared projection profile bins
+func bestSkew(points []image.Point, width, height int, from, to, step float64) float64 {
+	bestAngle, bestScore := 0.0, -1
+	// bins are shifted by width since projection of a rising line can be negative
+	bins := make([]int, height+2*width+1)
+	for i := 0; from+float64(i)*step <= to+step/2; i++ {
+		angle := from + float64(i)*step
+		clear(bins)
+		tan := math.Tan(angle * math.Pi / 180)
+		for _, p := range points {
+			bins[int(math.Round(float64(p.Y)+float64(p.X)*tan))+width]++
+		}
+		score := 0
+		for _, count := range bins {
+			score += count * count
+		}
+		if score > bestScore {
+			bestAngle, bestScore = angle, score
+		}
+	}
+	return bestAngle
+}
+
+// Deskew rotates image clockwise by skew angle keeping its size, uncovered corners are filled with bg
+func Deskew(img *image.RGBA, angle float64, bg color.RGBA) *image.RGBA {
+	rotated := Rotate(img, -angle)
+	b := img.Bounds()
+	offset := image.Pt((rotated.Bounds().Dx()-b.Dx())/2, (rotated.Bounds().Dy()-b.Dy())/2)
+	return Flatten(Crop(rotated, image.Rectangle{Min: offset, Max: offset.Add(b.Size())}), bg)
+}
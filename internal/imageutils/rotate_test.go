@@ -0,0 +1,130 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+type rotateRightTestCase struct {
+	comment string
+	turns   int
+	// expected position of top-left pixel of 3x2 image
+	expected image.Point
+}
+
+var RotateRightTestCase = []rotateRightTestCase{
+	{
+		comment:  "Quarter turn",
+		turns:    1,
+		expected: image.Pt(1, 0),
+	},
+	{
+		comment:  "Half turn",
+		turns:    2,
+		expected: image.Pt(2, 1),
+	},
+	{
+		comment:  "Three quarters",
+		turns:    3,
+		expected: image.Pt(0, 2),
+	},
+	{
+		comment:  "Full turn",
+		turns:    4,
+		expected: image.Pt(0, 0),
+	},
+}
+
+func TestRotateRight(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.SetRGBA(0, 0, red)
+
+	for _, tc := range RotateRightTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := RotateRight(img, tc.turns)
+			if got.RGBAAt(tc.expected.X, tc.expected.Y) != red {
+				t.Errorf("%s test. want red pixel at %v in %v image", tc.comment, tc.expected, got.Bounds())
+			}
+		})
+	}
+}
+
+type rotateRectRightTestCase struct {
+	comment     string
+	turns       int
+	expectedVal image.Rectangle
+}
+
+// rectangle 10,20-40,30 of 100x50 image
+var RotateRectRightTestCase = []rotateRectRightTestCase{
+	{comment: "No turn", turns: 0, expectedVal: image.Rect(10, 20, 40, 30)},
+	{comment: "Quarter turn", turns: 1, expectedVal: image.Rect(20, 10, 30, 40)},
+	{comment: "Half turn", turns: 2, expectedVal: image.Rect(60, 20, 90, 30)},
+	{comment: "Three quarters", turns: 3, expectedVal: image.Rect(20, 60, 30, 90)},
+}
+
+func TestRotateRectRight(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	rect := image.Rect(10, 20, 40, 30)
+	draw.Draw(img, rect, image.NewUniform(color.RGBA{R: 255, A: 255}), image.Point{}, draw.Src)
+
+	for _, tc := range RotateRectRightTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := RotateRectRight(rect, img.Bounds().Size(), tc.turns)
+			if got != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+			// rectangle covers the same pixels after rotation
+			if bounds := ContentBounds(RotateRight(img, tc.turns), 10); bounds != got {
+				t.Errorf("%s test. want rotated pixels at: %v, got: %v", tc.comment, got, bounds)
+			}
+		})
+	}
+}
+
+type skewTestCase struct {
+	comment string
+	angle   float64
+}
+
+var SkewTestCase = []skewTestCase{
+	{
+		comment: "Straight",
+		angle:   0,
+	},
+	{
+		comment: "Counterclockwise skew",
+		angle:   2.5,
+	},
+	{
+		comment: "Clockwise skew",
+		angle:   -1.2,
+	},
+}
+
+func TestSkewAngle(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	page := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			page.SetRGBA(x, y, white)
+			// text lines with gaps between words
+			if y%20 < 4 && x > 40 && x < 360 && x%50 < 40 {
+				page.SetRGBA(x, y, color.RGBA{A: 255})
+			}
+		}
+	}
+
+	for _, tc := range SkewTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := SkewAngle(Flatten(Rotate(page, tc.angle), white))
+			if math.Abs(got-tc.angle) > 0.2 {
+				t.Errorf("%s test. want %.2f, got: %.2f", tc.comment, tc.angle, got)
+			}
+		})
+	}
+}
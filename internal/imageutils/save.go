@@ -0,0 +1,64 @@
+package imageutils
+
+import (
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/tiff"
+)
+
+// MaxDimension returns the largest width or height of an image in a given image format.
+// Png and tiff have no such limit of their own, they are kept at jpg limit so images fit in memory
+// of encoders and viewers
+func MaxDimension(imgType string) int {
+	if imgType == "webp" {
+		return 16383
+	}
+	return 65535
+}
+
+// Save encodes image in a given image format with default quality and writes it to path
+func Save(path, imgType string, img image.Image) error {
+	return SaveQuality(path, imgType, img, jpeg.DefaultQuality)
+}
+
+// SaveQuality encodes image in a given image format and writes it to path,
+// quality (1-100) is used by lossy formats only
+func SaveQuality(path, imgType string, img image.Image, quality int) error {
+	return SaveDPI(path, imgType, img, quality, 0)
+}
+
+// SaveDPI is SaveQuality recording image resolution where the encoder supports it (bilevel TIFF),
+// zero dpi falls back to 72
+func SaveDPI(path, imgType string, img image.Image, quality int, dpi float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	switch imgType {
+	case "jpg", "jpeg":
+		err = jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
+	case "png":
+		// paletted image with 2 colors is written by png encoder with 1 bit per pixel
+		err = png.Encode(f, img)
+	case "webp":
+		// lossless encoding, there is no pure Go lossy webp encoder
+		err = nativewebp.Encode(f, img, nil)
+	case "tiff", "tif":
+		if bilevel, ok := img.(*image.Paletted); ok && len(bilevel.Palette) == 2 {
+			err = encodeBilevelTIFF(f, bilevel, dpi)
+		} else {
+			err = tiff.Encode(f, img, &tiff.Options{Compression: tiff.Deflate})
+		}
+	}
+
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
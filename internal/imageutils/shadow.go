@@ -0,0 +1,54 @@
+package imageutils
+
+import (
+	"image"
+	"math"
+)
+
+// Shadow renders soft black shadow of a rectangle of a given size. Returned image is larger
+// than size by blur radius on each side, so shadow edges can fade out
+func Shadow(size image.Point, radius int, opacity float64) *image.RGBA {
+	width, height := size.X+2*radius, size.Y+2*radius
+	alpha := make([]float64, width*height)
+	for y := radius; y < radius+size.Y; y++ {
+		for x := radius; x < radius+size.X; x++ {
+			alpha[y*width+x] = clamp01(opacity)
+		}
+	}
+
+	// three box blur passes approximate gaussian blur
+	for i := 0; i < 3; i++ {
+		alpha = boxBlur(alpha, width, height, radius/3+1, 1, width)
+		alpha = boxBlur(alpha, height, width, radius/3+1, width, 1)
+	}
+
+	shadow := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i, a := range alpha {
+		shadow.Pix[i*4+3] = uint8(math.Round(a * 255))
+	}
+	return shadow
+}
+
+// boxBlur averages values along lines of length n with given step between neighbours,
+// lines are lineStep apart. Used both for horizontal and vertical passes
+func boxBlur(src []float64, n, lines, r, step, lineStep int) []float64 {
+	dst := make([]float64, len(src))
+	for line := 0; line < lines; line++ {
+		start := line * lineStep
+		sum := 0.0
+		// running sum over window [i-r, i+r] with zero padding outside the line
+		for i := 0; i <= r && i < n; i++ {
+			sum += src[start+i*step]
+		}
+		for i := 0; i < n; i++ {
+			dst[start+i*step] = sum / float64(2*r+1)
+			if i+r+1 < n {
+				sum += src[start+(i+r+1)*step]
+			}
+			if i-r >= 0 {
+				sum -= src[start+(i-r)*step]
+			}
+		}
+	}
+	return dst
+}
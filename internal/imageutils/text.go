@@ -0,0 +1,82 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	defaultFont     *opentype.Font
+	defaultFontErr  error
+	defaultFontOnce sync.Once
+)
+
+// loadDefaultFont parses embedded Go Regular font once, so no system fonts are required
+func loadDefaultFont() (*opentype.Font, error) {
+	defaultFontOnce.Do(func() {
+		defaultFont, defaultFontErr = opentype.Parse(goregular.TTF)
+	})
+	return defaultFont, defaultFontErr
+}
+
+// LoadFont parses TrueType or OpenType font file
+func LoadFont(path string) (*opentype.Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return opentype.Parse(data)
+}
+
+// TextImage renders text with embedded font of a given size (in pixels) onto a transparent image
+func TextImage(text string, size float64, c color.Color) (*image.RGBA, error) {
+	fnt, err := loadDefaultFont()
+	if err != nil {
+		return nil, err
+	}
+	return FontTextImage(fnt, text, size, c)
+}
+
+// FontTextImage renders text with a given font and size (in pixels) onto a transparent image
+func FontTextImage(fnt *opentype.Font, text string, size float64, c color.Color) (*image.RGBA, error) {
+	// faces are not safe for concurrent use, so each call gets its own
+	face, err := opentype.NewFace(fnt, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	metrics := face.Metrics()
+	width := font.MeasureString(face, text).Ceil()
+	height := (metrics.Ascent + metrics.Descent).Ceil()
+
+	textImg := image.NewRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	drawer := font.Drawer{
+		Dst:  textImg,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.Point26_6{X: 0, Y: metrics.Ascent},
+	}
+	drawer.DrawString(text)
+
+	return textImg, nil
+}
+
+// DrawText draws text on dst with its top left corner placed at x,y
+func DrawText(dst draw.Image, text string, x, y int, size float64, c color.Color) error {
+	textImg, err := TextImage(text, size, c)
+	if err != nil {
+		return err
+	}
+	pos := image.Pt(x, y)
+	draw.Draw(dst, textImg.Bounds().Add(pos), textImg, image.Point{}, draw.Over)
+	return nil
+}
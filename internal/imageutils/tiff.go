@@ -0,0 +1,107 @@
+package imageutils
+
+import (
+	"encoding/binary"
+	"image"
+	"io"
+	"math"
+)
+
+// TIFF tags written for bilevel images
+const (
+	tagImageWidth      = 256
+	tagImageLength     = 257
+	tagBitsPerSample   = 258
+	tagCompression     = 259
+	tagPhotometric     = 262
+	tagStripOffsets    = 273
+	tagSamplesPerPixel = 277
+	tagRowsPerStrip    = 278
+	tagStripByteCounts = 279
+	tagXResolution     = 282
+	tagYResolution     = 283
+	tagResolutionUnit  = 296
+
+	dtShort    = 3
+	dtLong     = 4
+	dtRational = 5
+
+	// resolutionUnitInch is ResolutionUnit value for resolution in pixels per inch
+	resolutionUnitInch = 2
+	// resolution is stored as rational with this denominator
+	resolutionDenominator = 100
+)
+
+// encodeBilevelTIFF writes uncompressed 1-bit TIFF since x/image/tiff encoder supports only 8-bit samples.
+// Palette index 1 (white) is stored as set bit with BlackIsZero photometric interpretation,
+// dpi is written as resolution of the image, 72 when zero
+func encodeBilevelTIFF(w io.Writer, img *image.Paletted, dpi float64) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	rowBytes := (width + 7) / 8
+
+	data := make([]byte, rowBytes*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if img.Pix[y*img.Stride+x] != 0 {
+				data[y*rowBytes+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+
+	type entry struct {
+		tag, dataType uint16
+		value         uint32
+	}
+	entries := []entry{
+		{tagImageWidth, dtLong, uint32(width)},
+		{tagImageLength, dtLong, uint32(height)},
+		{tagBitsPerSample, dtShort, 1},
+		{tagCompression, dtShort, 1},
+		{tagPhotometric, dtShort, 1},
+		{tagStripOffsets, dtLong, 0},
+		{tagSamplesPerPixel, dtShort, 1},
+		{tagRowsPerStrip, dtLong, uint32(height)},
+		{tagStripByteCounts, dtLong, uint32(len(data))},
+		{tagXResolution, dtRational, 0},
+		{tagYResolution, dtRational, 0},
+		{tagResolutionUnit, dtShort, resolutionUnitInch},
+	}
+	// IFD follows header (8 bytes): count, 12 bytes per entry and next IFD offset,
+	// then both resolutions (8 bytes each) and image data
+	resolutionOffset := 8 + 2 + 12*len(entries) + 4
+	dataOffset := resolutionOffset + 16
+	entries[5].value = uint32(dataOffset)
+	entries[9].value = uint32(resolutionOffset)
+	entries[10].value = uint32(resolutionOffset + 8)
+
+	if dpi <= 0 {
+		dpi = 72
+	}
+	resolution := uint32(math.Round(dpi * resolutionDenominator))
+
+	buf := make([]byte, 0, dataOffset+len(data))
+	buf = append(buf, 'I', 'I', 42, 0)
+	buf = binary.LittleEndian.AppendUint32(buf, 8)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(entries)))
+	for _, e := range entries {
+		buf = binary.LittleEndian.AppendUint16(buf, e.tag)
+		buf = binary.LittleEndian.AppendUint16(buf, e.dataType)
+		buf = binary.LittleEndian.AppendUint32(buf, 1)
+		if e.dataType == dtShort {
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(e.value))
+			buf = binary.LittleEndian.AppendUint16(buf, 0)
+		} else {
+			buf = binary.LittleEndian.AppendUint32(buf, e.value)
+		}
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, 0)
+	for range 2 {
+		buf = binary.LittleEndian.AppendUint32(buf, resolution)
+		buf = binary.LittleEndian.AppendUint32(buf, resolutionDenominator)
+	}
+	buf = append(buf, data...)
+
+	_, err := w.Write(buf)
+	return err
+}
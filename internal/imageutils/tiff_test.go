@@ -0,0 +1,80 @@
+package imageutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/tiff"
+)
+
+// tiffTag returns value of a single valued IFD entry, rationals are returned as numerator/denominator
+func tiffTag(t *testing.T, data []byte, tag uint16) float64 {
+	t.Helper()
+	le := binary.LittleEndian
+	ifd := le.Uint32(data[4:])
+	count := int(le.Uint16(data[ifd:]))
+	for i := 0; i < count; i++ {
+		e := data[int(ifd)+2+12*i:]
+		if le.Uint16(e) != tag {
+			continue
+		}
+		switch le.Uint16(e[2:]) {
+		case dtShort:
+			return float64(le.Uint16(e[8:]))
+		case dtRational:
+			offset := le.Uint32(e[8:])
+			return float64(le.Uint32(data[offset:])) / float64(le.Uint32(data[offset+4:]))
+		}
+		return float64(le.Uint32(e[8:]))
+	}
+	t.Fatalf("tag %d is missing", tag)
+	return 0
+}
+
+type bilevelTIFFTestCase struct {
+	comment     string
+	dpi         float64
+	expectedVal float64
+}
+
+var BilevelTIFFTestCase = []bilevelTIFFTestCase{
+	{comment: "Render resolution", dpi: 300, expectedVal: 300},
+	{comment: "Fractional resolution", dpi: 112.5, expectedVal: 112.5},
+	{comment: "Unknown resolution", dpi: 0, expectedVal: 72},
+}
+
+func TestEncodeBilevelTIFF(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 10, 3), BilevelPalette)
+	img.SetColorIndex(9, 1, 1)
+
+	for _, tc := range BilevelTIFFTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := encodeBilevelTIFF(&buf, img, tc.dpi); err != nil {
+				t.Fatal(err)
+			}
+			data := buf.Bytes()
+			for _, tag := range []uint16{tagXResolution, tagYResolution} {
+				if got := tiffTag(t, data, tag); got != tc.expectedVal {
+					t.Errorf("%s test. tag %d want: %v, got: %v", tc.comment, tag, tc.expectedVal, got)
+				}
+			}
+			if got := tiffTag(t, data, tagResolutionUnit); got != resolutionUnitInch {
+				t.Errorf("%s test. resolution unit want: %v, got: %v", tc.comment, resolutionUnitInch, got)
+			}
+
+			decoded, err := tiff.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			white := color.GrayModel.Convert(decoded.At(9, 1)).(color.Gray).Y
+			black := color.GrayModel.Convert(decoded.At(8, 1)).(color.Gray).Y
+			if white != 255 || black != 0 {
+				t.Errorf("%s test. want white pixel among black ones, got: %d next to %d", tc.comment, white, black)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package imageutils
+
+import "image"
+
+// Invert inverts colors of the image keeping its alpha channel
+func Invert(img *image.RGBA) *image.RGBA {
+	dstImg := image.NewRGBA(img.Bounds())
+	for i := 0; i < len(img.Pix); i += 4 {
+		a := img.Pix[i+3]
+		// colors are premultiplied, so inverted channel is a - c instead of 255 - c
+		dstImg.Pix[i] = a - img.Pix[i]
+		dstImg.Pix[i+1] = a - img.Pix[i+1]
+		dstImg.Pix[i+2] = a - img.Pix[i+2]
+		dstImg.Pix[i+3] = a
+	}
+	return dstImg
+}
+
+// InvertLightness inverts HSL lightness of every pixel keeping its hue and saturation,
+// so white pages become dark while colored charts stay recognizable.
+// Shifting all channels by a - max - min maps lightness L to 1 - L and keeps chroma
+func InvertLightness(img *image.RGBA) *image.RGBA {
+	dstImg := image.NewRGBA(img.Bounds())
+	for i := 0; i < len(img.Pix); i += 4 {
+		r, g, b, a := int(img.Pix[i]), int(img.Pix[i+1]), int(img.Pix[i+2]), img.Pix[i+3]
+		shift := int(a) - max(r, g, b) - min(r, g, b)
+		dstImg.Pix[i] = uint8(r + shift)
+		dstImg.Pix[i+1] = uint8(g + shift)
+		dstImg.Pix[i+2] = uint8(b + shift)
+		dstImg.Pix[i+3] = a
+	}
+	return dstImg
+}
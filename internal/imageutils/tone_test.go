@@ -0,0 +1,54 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type invertLightnessTestCase struct {
+	comment     string
+	inputValue  color.RGBA
+	expectedVal color.RGBA
+}
+
+var InvertLightnessTestCase = []invertLightnessTestCase{
+	{
+		comment:     "White becomes black",
+		inputValue:  color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		expectedVal: color.RGBA{A: 255},
+	},
+	{
+		comment:     "Black becomes white",
+		inputValue:  color.RGBA{A: 255},
+		expectedVal: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	},
+	{
+		comment:     "Pure red keeps lightness",
+		inputValue:  color.RGBA{R: 255, A: 255},
+		expectedVal: color.RGBA{R: 255, A: 255},
+	},
+	{
+		comment:     "Dark blue becomes light blue",
+		inputValue:  color.RGBA{B: 128, A: 255},
+		expectedVal: color.RGBA{R: 127, G: 127, B: 255, A: 255},
+	},
+	{
+		comment:     "Transparent stays transparent",
+		inputValue:  color.RGBA{},
+		expectedVal: color.RGBA{},
+	},
+}
+
+func TestInvertLightness(t *testing.T) {
+	for _, tc := range InvertLightnessTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+			img.SetRGBA(0, 0, tc.inputValue)
+			got := InvertLightness(img).RGBAAt(0, 0)
+			if got != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package imageutils
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// ContentBounds finds bounding box of pixels differing from the background by more than tolerance.
+// Background is taken from the top left corner of the image. Empty rectangle is returned for blank image
+func ContentBounds(img *image.RGBA, tolerance int) image.Rectangle {
+	b := img.Bounds()
+	if b.Empty() {
+		return image.Rectangle{}
+	}
+	bg := img.RGBAAt(b.Min.X, b.Min.Y)
+
+	isContent := func(x, y int) bool {
+		c := img.RGBAAt(x, y)
+		return absDiff(c.R, bg.R) > tolerance || absDiff(c.G, bg.G) > tolerance || absDiff(c.B, bg.B) > tolerance
+	}
+	rowHasContent := func(y, x0, x1 int) bool {
+		for x := x0; x < x1; x++ {
+			if isContent(x, y) {
+				return true
+			}
+		}
+		return false
+	}
+	colHasContent := func(x, y0, y1 int) bool {
+		for y := y0; y < y1; y++ {
+			if isContent(x, y) {
+				return true
+			}
+		}
+		return false
+	}
+
+	top := b.Min.Y
+	for top < b.Max.Y && !rowHasContent(top, b.Min.X, b.Max.X) {
+		top++
+	}
+	if top == b.Max.Y {
+		return image.Rectangle{}
+	}
+	bottom := b.Max.Y
+	for !rowHasContent(bottom-1, b.Min.X, b.Max.X) {
+		bottom--
+	}
+	left := b.Min.X
+	for !colHasContent(left, top, bottom) {
+		left++
+	}
+	right := b.Max.X
+	for !colHasContent(right-1, top, bottom) {
+		right--
+	}
+
+	return image.Rect(left, top, right, bottom)
+}
+
+// Crop copies rectangle of the image into a new image with origin at 0,0
+func Crop(img *image.RGBA, rect image.Rectangle) *image.RGBA {
+	rect = rect.Intersect(img.Bounds())
+	dstImg := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dstImg, dstImg.Bounds(), img, rect.Min, draw.Src)
+	return dstImg
+}
+
+// absDiff returns absolute difference of two color channels
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
@@ -0,0 +1,62 @@
+package imageutils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type contentBoundsTestCase struct {
+	comment     string
+	content     image.Rectangle
+	tolerance   int
+	expectedVal image.Rectangle
+}
+
+var ContentBoundsTestCase = []contentBoundsTestCase{
+	{
+		comment:     "Content in the middle",
+		content:     image.Rect(20, 30, 60, 70),
+		tolerance:   10,
+		expectedVal: image.Rect(20, 30, 60, 70),
+	},
+	{
+		comment:     "Content touches the edge",
+		content:     image.Rect(50, 0, 100, 10),
+		tolerance:   10,
+		expectedVal: image.Rect(50, 0, 100, 10),
+	},
+	{
+		comment:     "Blank page",
+		content:     image.Rectangle{},
+		tolerance:   10,
+		expectedVal: image.Rectangle{},
+	},
+	{
+		comment:     "Content within tolerance",
+		content:     image.Rect(20, 30, 60, 70),
+		tolerance:   255,
+		expectedVal: image.Rectangle{},
+	},
+}
+
+func TestContentBounds(t *testing.T) {
+	for _, tc := range ContentBoundsTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+			for y := 0; y < 100; y++ {
+				for x := 0; x < 100; x++ {
+					c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+					if image.Pt(x, y).In(tc.content) {
+						c = color.RGBA{A: 255}
+					}
+					img.SetRGBA(x, y, c)
+				}
+			}
+			got := ContentBounds(img, tc.tolerance)
+			if got != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package input
 
 import (
+	"bufio"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"image/color"
+	"io"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
 )
 
 var (
@@ -121,3 +128,132 @@ func ImgSizeExtractor(s string) (int, int, error) {
 	}
 	return x, y, nil
 }
+
+// ErrInvalidColor is returned when color is not in #RRGGBB hex notation
+var ErrInvalidColor = errors.New("color must be in #RRGGBB format")
+
+// ColorExtractor parses color in hex notation like #1f4986 into RGBA color
+func ColorExtractor(s string) (color.RGBA, error) {
+	rgb, err := hex.DecodeString(strings.TrimPrefix(s, "#"))
+	if err != nil || len(rgb) != 3 {
+		return color.RGBA{}, ErrInvalidColor
+	}
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}, nil
+}
+
+var (
+	// ErrCropFormat is returned when crop region doesn't consist of 4 comma separated values
+	ErrCropFormat = errors.New("crop must be in x,y,w,h format")
+	// ErrCropValue is returned when crop value is not a number with optional pt, px or % unit
+	ErrCropValue = errors.New("crop value must be a number with optional pt, px or % unit")
+	// ErrCropSize is returned when crop width or height is not positive
+	ErrCropSize = errors.New("crop width and height must be positive")
+)
+
+// CropExtractor parses crop region like 72,72,300,200 or 10%,10%,80%,50%.
+// Values without a unit are treated as points (page coordinates)
+func CropExtractor(s string) (imageutils.Region, error) {
+	parts := strings.Split(strings.ReplaceAll(s, " ", ""), ",")
+	if len(parts) != 4 {
+		return imageutils.Region{}, ErrCropFormat
+	}
+
+	var lengths [4]imageutils.Length
+	for i, part := range parts {
+		unit := imageutils.Points
+		for _, u := range []imageutils.Unit{imageutils.Points, imageutils.Pixels, imageutils.Percent} {
+			if strings.HasSuffix(part, string(u)) {
+				unit = u
+				part = strings.TrimSuffix(part, string(u))
+				break
+			}
+		}
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil || value < 0 {
+			return imageutils.Region{}, ErrCropValue
+		}
+		lengths[i] = imageutils.Length{Value: value, Unit: unit}
+	}
+	if lengths[2].Value == 0 || lengths[3].Value == 0 {
+		return imageutils.Region{}, ErrCropSize
+	}
+
+	return imageutils.Region{X: lengths[0], Y: lengths[1], W: lengths[2], H: lengths[3]}, nil
+}
+
+// ErrCropSpecLine is returned when crop spec file line is not in "pages region" format
+var ErrCropSpecLine = errors.New("crop spec line must be: pages x,y,w,h")
+
+// CropSpecExtractor parses per page crop overrides, one "pages region" pair per line, example:
+//
+//	# pages   region
+//	1-3       10%,10%,80%,50%
+//	7         72,72,300,200
+//
+// Returned map is keyed by page numbers starting from 1
+func CropSpecExtractor(r io.Reader, pageCount int) (map[int]imageutils.Region, error) {
+	regions := make(map[int]imageutils.Region)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: %w", lineNum, ErrCropSpecLine)
+		}
+		pages, err := PagesExtractor(fields[0], pageCount)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		region, err := CropExtractor(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		for _, pageNum := range pages {
+			regions[pageNum] = region
+		}
+	}
+	return regions, scanner.Err()
+}
+
+var (
+	// ErrRenditionFormat is returned when rendition is not in NNNw or NNNw:format notation
+	ErrRenditionFormat = errors.New("rendition must be in NNNw or NNNw:format format, e.g. 640w:webp")
+	// ErrRenditionWidth is returned when rendition width is not a positive integer
+	ErrRenditionWidth = errors.New("rendition width must be positive integer")
+)
+
+// Rendition is a single entry of responsive image set: output width in pixels and image format
+type Rendition struct {
+	Width   int
+	ImgType string
+}
+
+// RenditionsExtractor parses list of renditions like 320w,640w,1280w:webp,2560w:jpg.
+// Renditions without explicit format use imgType
+func RenditionsExtractor(s, imgType string) ([]Rendition, error) {
+	var renditions []Rendition
+	for _, part := range strings.Split(strings.ReplaceAll(s, " ", ""), ",") {
+		widthStr, format, hasFormat := strings.Cut(part, ":")
+		if !strings.HasSuffix(widthStr, "w") || (hasFormat && format == "") {
+			return nil, ErrRenditionFormat
+		}
+		width, err := strconv.Atoi(strings.TrimSuffix(widthStr, "w"))
+		if err != nil || width <= 0 {
+			return nil, ErrRenditionWidth
+		}
+		if !hasFormat {
+			format = imgType
+		}
+		format = strings.ToLower(format)
+		if err := ImgFormatValidator(format); err != nil {
+			return nil, err
+		}
+		renditions = append(renditions, Rendition{Width: width, ImgType: format})
+	}
+	return renditions, nil
+}
@@ -3,6 +3,7 @@ package input
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -200,3 +201,164 @@ func TestImgSizeExtractor(t *testing.T) {
 		})
 	}
 }
+
+var ColorTestCase = []validParamTestCase{
+	{
+		comment:     "Color is valid",
+		inputValue:  "#1f4986",
+		expectError: nil,
+	},
+	{
+		comment:     "Color without hash",
+		inputValue:  "FFFFFF",
+		expectError: nil,
+	},
+	{
+		comment:     "Short notation",
+		inputValue:  "#fff",
+		expectError: ErrInvalidColor,
+	},
+	{
+		comment:     "Not hex",
+		inputValue:  "#zz0000",
+		expectError: ErrInvalidColor,
+	},
+	{
+		comment:     "Color name",
+		inputValue:  "white",
+		expectError: ErrInvalidColor,
+	},
+}
+
+func TestColorExtractor(t *testing.T) {
+	for _, tc := range ColorTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			_, err := ColorExtractor(tc.inputValue)
+			if !errors.Is(err, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, err)
+			}
+		})
+	}
+}
+
+var CropTestCase = []validParamTestCase{
+	{
+		comment:     "Crop in points",
+		inputValue:  "72,72,300,200",
+		expectError: nil,
+	},
+	{
+		comment:     "Crop in mixed units",
+		inputValue:  "10%, 100px, 80%, 300pt",
+		expectError: nil,
+	},
+	{
+		comment:     "Three values",
+		inputValue:  "10,10,100",
+		expectError: ErrCropFormat,
+	},
+	{
+		comment:     "Unknown unit",
+		inputValue:  "10mm,10,100,100",
+		expectError: ErrCropValue,
+	},
+	{
+		comment:     "Negative value",
+		inputValue:  "-10,10,100,100",
+		expectError: ErrCropValue,
+	},
+	{
+		comment:     "Zero width",
+		inputValue:  "10,10,0,100",
+		expectError: ErrCropSize,
+	},
+}
+
+var CropSpecTestCase = []validParamTestCase{
+	{
+		comment:     "Spec is valid",
+		inputValue:  "# pages region\n1-3 10%,10%,80%,50%\n\n7 72,72,300,200\n",
+		expectError: nil,
+	},
+	{
+		comment:     "Region missing",
+		inputValue:  "1-3\n",
+		expectError: ErrCropSpecLine,
+	},
+	{
+		comment:     "Page out of range",
+		inputValue:  "1-300 10,10,100,100\n",
+		expectError: ErrPageOutofRange,
+	},
+	{
+		comment:     "Invalid region",
+		inputValue:  "5 10,10,100\n",
+		expectError: ErrCropFormat,
+	},
+}
+
+func TestCropExtractor(t *testing.T) {
+	for _, tc := range CropTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			_, err := CropExtractor(tc.inputValue)
+			if !errors.Is(err, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestCropSpecExtractor(t *testing.T) {
+	for _, tc := range CropSpecTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			_, err := CropSpecExtractor(strings.NewReader(tc.inputValue), 100)
+			if !errors.Is(err, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, err)
+			}
+		})
+	}
+}
+
+var RenditionsTestCase = []validParamTestCase{
+	{
+		comment:     "Widths only",
+		inputValue:  "320w,640w",
+		expectError: nil,
+	},
+	{
+		comment:     "Widths with formats",
+		inputValue:  "320w, 1280w:webp, 2560w:JPG",
+		expectError: nil,
+	},
+	{
+		comment:     "No w suffix",
+		inputValue:  "320,640w",
+		expectError: ErrRenditionFormat,
+	},
+	{
+		comment:     "Empty format",
+		inputValue:  "320w:",
+		expectError: ErrRenditionFormat,
+	},
+	{
+		comment:     "Zero width",
+		inputValue:  "0w",
+		expectError: ErrRenditionWidth,
+	},
+	{
+		comment:     "Unsupported format",
+		inputValue:  "320w:bmp",
+		expectError: ErrUnsupportedImgFormat,
+	},
+}
+
+func TestRenditionsExtractor(t *testing.T) {
+	for _, tc := range RenditionsTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			_, err := RenditionsExtractor(tc.inputValue, "png")
+			if !errors.Is(err, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, err)
+			}
+		})
+	}
+}
@@ -2,25 +2,208 @@ package input
 
 import (
 	"errors"
+	"regexp"
 	"strings"
+
+	"github.com/dmikhr/pdfjuicer/internal/command"
 )
 
-var allowedImgFormats = []string{"png", "jpg", "jpeg"}
+var allowedImgFormats = []string{"png", "jpg", "jpeg", "tiff", "tif", "webp"}
 
 // ErrUnsupportedImgFormat validates image format
 var ErrUnsupportedImgFormat = errors.New("unsupported image format")
 
 // ImgFormatValidator validates if submitted image format (e.g. png, jpg) is supported
 func ImgFormatValidator(imgFormat string) error {
-	imgFormatLow := strings.ToLower(imgFormat)
-	for _, allowedFormat := range allowedImgFormats {
-		if imgFormatLow == allowedFormat {
-			return nil
-		}
+	if isAllowed(strings.ToLower(imgFormat), allowedImgFormats) {
+		return nil
 	}
 	return ErrUnsupportedImgFormat
 }
 
+var allowedTilesFormats = []string{"dzi", "iiif", "xyz"}
+
+// ErrUnsupportedTilesFormat is returned when tile pyramid layout is not supported
+var ErrUnsupportedTilesFormat = errors.New("unsupported tiles format")
+
+// TilesFormatValidator validates if submitted tile pyramid layout (dzi, iiif, xyz) is supported
+func TilesFormatValidator(tilesFormat string) error {
+	if isAllowed(tilesFormat, allowedTilesFormats) {
+		return nil
+	}
+	return ErrUnsupportedTilesFormat
+}
+
+var allowedColorModes = []string{"rgb", "gray", "bw"}
+
+// ErrUnsupportedColorMode is returned when output color mode is not supported
+var ErrUnsupportedColorMode = errors.New("unsupported color mode")
+
+// ColorModeValidator validates if submitted output color mode (rgb, gray, bw) is supported
+func ColorModeValidator(colorMode string) error {
+	if isAllowed(colorMode, allowedColorModes) {
+		return nil
+	}
+	return ErrUnsupportedColorMode
+}
+
+var allowedBilevelMethods = []string{"threshold", "otsu", "dither"}
+
+// ErrUnsupportedBilevelMethod is returned when method of conversion to black and white is not supported
+var ErrUnsupportedBilevelMethod = errors.New("unsupported bilevel method")
+
+// BilevelMethodValidator validates if submitted black and white conversion method (threshold, otsu, dither) is supported
+func BilevelMethodValidator(method string) error {
+	if isAllowed(method, allowedBilevelMethods) {
+		return nil
+	}
+	return ErrUnsupportedBilevelMethod
+}
+
+var allowedPositions = []string{
+	"center", "top", "bottom", "left", "right",
+	"top-left", "top-right", "bottom-left", "bottom-right",
+}
+
+// ErrUnsupportedPosition is returned when position of an element on the page is not supported
+var ErrUnsupportedPosition = errors.New("unsupported position")
+
+// PositionValidator validates position of an element on the page like center, top or bottom-right
+func PositionValidator(position string) error {
+	if isAllowed(position, allowedPositions) {
+		return nil
+	}
+	return ErrUnsupportedPosition
+}
+
+// WatermarkPositionValidator validates position of a watermark, which is either a position
+// on the page or tile to repeat watermark over the page
+func WatermarkPositionValidator(position string) error {
+	if position == "tile" {
+		return nil
+	}
+	return PositionValidator(position)
+}
+
+var allowedPlaceholders = []string{"{doc}", "{page}", "{total}"}
+
+// ErrUnknownPlaceholder is returned when template contains placeholder which can't be filled
+var ErrUnknownPlaceholder = errors.New("unknown placeholder")
+
+var placeholderRe = regexp.MustCompile(`\{[^{}]*\}`)
+
+// CaptionTemplateValidator validates that caption template uses only {doc}, {page} and {total} placeholders
+func CaptionTemplateValidator(template string) error {
+	return placeholdersValidator(template, allowedPlaceholders)
+}
+
+var allowedNamePlaceholders = []string{"{page}", "{prefix}", "{postfix}"}
+
+var (
+	// ErrEmptyName is returned when file name template is empty
+	ErrEmptyName = errors.New("file name template is empty")
+	// ErrMissingPlaceholder is returned when template lacks a required placeholder
+	ErrMissingPlaceholder = errors.New("missing placeholder")
+)
+
+// NameTemplateValidator validates file name template like thumb_{page}:
+// only {page}, {prefix} and {postfix} placeholders and file name characters are allowed,
+// {page} is required so pages don't overwrite each other
+func NameTemplateValidator(template string) error {
+	return nameTemplateValidator(template, allowedNamePlaceholders, "{page}")
+}
+
+var allowedImageNamePlaceholders = []string{"{page}", "{index}", "{prefix}", "{postfix}"}
+
+// ImageNameTemplateValidator validates name template of embedded images like {prefix}{page}_img{index}:
+// {index} placeholder is allowed besides {page}, {prefix} and {postfix}, both {page} and {index}
+// are required so images don't overwrite each other
+func ImageNameTemplateValidator(template string) error {
+	return nameTemplateValidator(template, allowedImageNamePlaceholders, "{page}", "{index}")
+}
+
+// ErrEmptyCommand is returned when command template has no program
+var ErrEmptyCommand = errors.New("command is empty")
+
+var allowedOCRPlaceholders = []string{"{image}", "{output}"}
+
+// OCRCommandValidator validates OCR command like tesseract {image} {output} txt hocr:
+// {image} placeholder is required, {output} is the path of recognized files without extension
+func OCRCommandValidator(template string) error {
+	return commandValidator(template, allowedOCRPlaceholders, "{image}")
+}
+
+var allowedExecPagePlaceholders = []string{"{path}", "{page}"}
+
+// ExecPageCommandValidator validates page hook command like oxipng {path}:
+// {path} placeholder is required, {page} is page number
+func ExecPageCommandValidator(template string) error {
+	return commandValidator(template, allowedExecPagePlaceholders, "{path}")
+}
+
+var allowedExecDonePlaceholders = []string{"{manifest}", "{dir}"}
+
+// ExecDoneCommandValidator validates command run after extraction like upload {manifest}:
+// only {manifest} and {dir} placeholders are allowed, none is required
+func ExecDoneCommandValidator(template string) error {
+	return commandValidator(template, allowedExecDonePlaceholders, "")
+}
+
+// commandValidator checks that command is not empty, has closed quotes, uses allowed placeholders
+// and contains required one if any
+func commandValidator(template string, allowed []string, required string) error {
+	if strings.TrimSpace(template) == "" {
+		return ErrEmptyCommand
+	}
+	if _, err := command.Parse(template); err != nil {
+		return err
+	}
+	if err := placeholdersValidator(template, allowed); err != nil {
+		return err
+	}
+	if required != "" && !strings.Contains(template, required) {
+		return ErrMissingPlaceholder
+	}
+	return nil
+}
+
+// nameTemplateValidator checks that template is not empty and is a file name with allowed
+// placeholders containing all required ones
+func nameTemplateValidator(template string, allowed []string, required ...string) error {
+	if template == "" {
+		return ErrEmptyName
+	}
+	if err := placeholdersValidator(template, allowed); err != nil {
+		return err
+	}
+	for _, placeholder := range required {
+		if !strings.Contains(template, placeholder) {
+			return ErrMissingPlaceholder
+		}
+	}
+	return FilenameValidator(placeholderRe.ReplaceAllString(template, ""))
+}
+
+// placeholdersValidator checks that template uses only allowed placeholders
+func placeholdersValidator(template string, allowed []string) error {
+	for _, placeholder := range placeholderRe.FindAllString(template, -1) {
+		if !isAllowed(placeholder, allowed) {
+			return ErrUnknownPlaceholder
+		}
+	}
+	return nil
+}
+
+// isAllowed checks if value is in the list of allowed values
+func isAllowed(value string, allowed []string) bool {
+	for _, allowedValue := range allowed {
+		if value == allowedValue {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	// ErrInputLong is returned when the provided input exceeds the allowed maximum length
 	ErrInputLong = errors.New("input too long")
@@ -3,6 +3,8 @@ package input
 import (
 	"errors"
 	"testing"
+
+	"github.com/dmikhr/pdfjuicer/internal/command"
 )
 
 type validParamTestCase struct {
@@ -61,8 +63,13 @@ var ImgFormatTestCase = []validParamTestCase{
 		expectError: nil,
 	},
 	{
-		comment:     "Unsupported tiff",
+		comment:     "Supports tiff",
 		inputValue:  "tiff",
+		expectError: nil,
+	},
+	{
+		comment:     "Unsupported bmp",
+		inputValue:  "bmp",
 		expectError: ErrUnsupportedImgFormat,
 	},
 	{
@@ -72,6 +79,259 @@ var ImgFormatTestCase = []validParamTestCase{
 	},
 }
 
+var TilesFormatTestCase = []validParamTestCase{
+	{
+		comment:     "Supports dzi",
+		inputValue:  "dzi",
+		expectError: nil,
+	},
+	{
+		comment:     "Supports iiif",
+		inputValue:  "iiif",
+		expectError: nil,
+	},
+	{
+		comment:     "Supports xyz",
+		inputValue:  "xyz",
+		expectError: nil,
+	},
+	{
+		comment:     "Unsupported tms",
+		inputValue:  "tms",
+		expectError: ErrUnsupportedTilesFormat,
+	},
+}
+
+var ColorModeTestCase = []validParamTestCase{
+	{
+		comment:     "Supports gray",
+		inputValue:  "gray",
+		expectError: nil,
+	},
+	{
+		comment:     "Supports bw",
+		inputValue:  "bw",
+		expectError: nil,
+	},
+	{
+		comment:     "Unsupported cmyk",
+		inputValue:  "cmyk",
+		expectError: ErrUnsupportedColorMode,
+	},
+}
+
+var BilevelMethodTestCase = []validParamTestCase{
+	{
+		comment:     "Supports otsu",
+		inputValue:  "otsu",
+		expectError: nil,
+	},
+	{
+		comment:     "Supports dither",
+		inputValue:  "dither",
+		expectError: nil,
+	},
+	{
+		comment:     "Unsupported ordered",
+		inputValue:  "ordered",
+		expectError: ErrUnsupportedBilevelMethod,
+	},
+}
+
+var PositionTestCase = []validParamTestCase{
+	{
+		comment:     "Supports center",
+		inputValue:  "center",
+		expectError: nil,
+	},
+	{
+		comment:     "Supports corner",
+		inputValue:  "bottom-right",
+		expectError: nil,
+	},
+	{
+		comment:     "Reversed corner",
+		inputValue:  "right-bottom",
+		expectError: ErrUnsupportedPosition,
+	},
+	{
+		comment:     "Unsupported middle",
+		inputValue:  "middle",
+		expectError: ErrUnsupportedPosition,
+	},
+}
+
+var WatermarkPositionTestCase = []validParamTestCase{
+	{
+		comment:     "Supports corner",
+		inputValue:  "top-left",
+		expectError: nil,
+	},
+	{
+		comment:     "Supports tile",
+		inputValue:  "tile",
+		expectError: nil,
+	},
+	{
+		comment:     "Unsupported repeat",
+		inputValue:  "repeat",
+		expectError: ErrUnsupportedPosition,
+	},
+}
+
+var CaptionTemplateTestCase = []validParamTestCase{
+	{
+		comment:     "All placeholders",
+		inputValue:  "{doc} — p.{page}/{total}",
+		expectError: nil,
+	},
+	{
+		comment:     "Plain text",
+		inputValue:  "Course materials",
+		expectError: nil,
+	},
+	{
+		comment:     "Unknown placeholder",
+		inputValue:  "{doc} {date}",
+		expectError: ErrUnknownPlaceholder,
+	},
+}
+
+var NameTemplateTestCase = []validParamTestCase{
+	{
+		comment:     "Page placeholder",
+		inputValue:  "thumb_{page}",
+		expectError: nil,
+	},
+	{
+		comment:     "Prefix and postfix",
+		inputValue:  "{prefix}{page}{postfix}.small",
+		expectError: nil,
+	},
+	{
+		comment:     "Empty template",
+		inputValue:  "",
+		expectError: ErrEmptyName,
+	},
+	{
+		comment:     "Unknown placeholder",
+		inputValue:  "thumb_{total}",
+		expectError: ErrUnknownPlaceholder,
+	},
+	{
+		comment:     "Path separator",
+		inputValue:  "../thumb_{page}",
+		expectError: ErrInvalidChar,
+	},
+	{
+		comment:     "Missing page placeholder",
+		inputValue:  "{prefix}thumb",
+		expectError: ErrMissingPlaceholder,
+	},
+}
+
+var ImageNameTemplateTestCase = []validParamTestCase{
+	{
+		comment:     "Page and index placeholders",
+		inputValue:  "{prefix}{page}_img{index}",
+		expectError: nil,
+	},
+	{
+		comment:     "Unknown placeholder",
+		inputValue:  "img_{doc}_{page}_{index}",
+		expectError: ErrUnknownPlaceholder,
+	},
+	{
+		comment:     "Missing index placeholder",
+		inputValue:  "{prefix}{page}_img",
+		expectError: ErrMissingPlaceholder,
+	},
+	{
+		comment:     "Missing page placeholder",
+		inputValue:  "img{index}",
+		expectError: ErrMissingPlaceholder,
+	},
+	{
+		comment:     "Empty template",
+		inputValue:  "",
+		expectError: ErrEmptyName,
+	},
+}
+
+var OCRCommandTestCase = []validParamTestCase{
+	{
+		comment:     "Tesseract command",
+		inputValue:  "tesseract {image} {output} txt hocr",
+		expectError: nil,
+	},
+	{
+		comment:     "Unknown placeholder",
+		inputValue:  "tesseract {image} {page}",
+		expectError: ErrUnknownPlaceholder,
+	},
+	{
+		comment:     "Missing image placeholder",
+		inputValue:  "tesseract page.png {output}",
+		expectError: ErrMissingPlaceholder,
+	},
+	{
+		comment:     "Empty command",
+		inputValue:  "  ",
+		expectError: ErrEmptyCommand,
+	},
+}
+
+var ExecPageCommandTestCase = []validParamTestCase{
+	{
+		comment:     "Path and page placeholders",
+		inputValue:  "upload --page {page} {path}",
+		expectError: nil,
+	},
+	{
+		comment:     "Missing path placeholder",
+		inputValue:  "oxipng {page}",
+		expectError: ErrMissingPlaceholder,
+	},
+	{
+		comment:     "Placeholder of done hook",
+		inputValue:  "oxipng {path} {manifest}",
+		expectError: ErrUnknownPlaceholder,
+	},
+	{
+		comment:     "Quoted shell script",
+		inputValue:  `sh -c 'exit 3' {path}`,
+		expectError: nil,
+	},
+	{
+		comment:     "Unterminated quote",
+		inputValue:  `sh -c "optimize {path}`,
+		expectError: command.ErrUnterminatedQuote,
+	},
+}
+
+var ExecDoneCommandTestCase = []validParamTestCase{
+	{
+		comment:     "Manifest placeholder",
+		inputValue:  "publish {manifest}",
+		expectError: nil,
+	},
+	{
+		comment:     "No placeholders",
+		inputValue:  "notify-send done",
+		expectError: nil,
+	},
+	{
+		comment:     "Placeholder of page hook",
+		inputValue:  "publish {path}",
+		expectError: ErrUnknownPlaceholder,
+	},
+	{
+		comment:     "Empty command",
+		inputValue:  "",
+		expectError: ErrEmptyCommand,
+	},
+}
+
 func TestImgFormatValidator(t *testing.T) {
 	for _, tc := range ImgFormatTestCase {
 		t.Run(tc.comment, func(t *testing.T) {
@@ -93,3 +353,124 @@ func TestFilenameValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestTilesFormatValidator(t *testing.T) {
+	for _, tc := range TilesFormatTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := TilesFormatValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestColorModeValidator(t *testing.T) {
+	for _, tc := range ColorModeTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := ColorModeValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestBilevelMethodValidator(t *testing.T) {
+	for _, tc := range BilevelMethodTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := BilevelMethodValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestPositionValidator(t *testing.T) {
+	for _, tc := range PositionTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := PositionValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestWatermarkPositionValidator(t *testing.T) {
+	for _, tc := range WatermarkPositionTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := WatermarkPositionValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestCaptionTemplateValidator(t *testing.T) {
+	for _, tc := range CaptionTemplateTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := CaptionTemplateValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestNameTemplateValidator(t *testing.T) {
+	for _, tc := range NameTemplateTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := NameTemplateValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestImageNameTemplateValidator(t *testing.T) {
+	for _, tc := range ImageNameTemplateTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := ImageNameTemplateValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestOCRCommandValidator(t *testing.T) {
+	for _, tc := range OCRCommandTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := OCRCommandValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestExecPageCommandValidator(t *testing.T) {
+	for _, tc := range ExecPageCommandTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := ExecPageCommandValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestExecDoneCommandValidator(t *testing.T) {
+	for _, tc := range ExecDoneCommandTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			got := ExecDoneCommandValidator(tc.inputValue)
+			if !errors.Is(got, tc.expectError) {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectError, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package layout
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gen2brain/go-fitz"
+)
+
+// glyph box in em units relative to baseline origin, go-fitz gives no font metrics
+const (
+	ascent  = 0.8
+	descent = 0.2
+	// width of glyphs without outline which are not spaces
+	defaultWidth = 0.5
+	// gap between glyphs in em units which separates words
+	wordGap = 0.25
+	// baseline shift in em units which starts a new line
+	lineShift = 0.3
+)
+
+var (
+	// glyph outlines in go-fitz SVG output
+	outlineRe = regexp.MustCompile(`<path id="(font_[^"]+)" d="([^"]*)"/>`)
+	// glyphs placed on the page, transform maps glyph space in em to page points
+	useRe     = regexp.MustCompile(`<use data-text="([^"]*)" xlink:href="#(font_[^"]+)" transform="matrix\(([^)]*)\)"/>`)
+	numberRe  = regexp.MustCompile(`-?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+	commandRe = regexp.MustCompile(numberRe.String() + `|[A-Za-z]`)
+)
+
+// Box is a rectangle in points with origin at the top left corner of the page
+type Box struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Union returns box covering both boxes, zero box is ignored
+func (b Box) Union(o Box) Box {
+	if b == (Box{}) {
+		return o
+	}
+	if o == (Box{}) {
+		return b
+	}
+	x0, y0 := min(b.X, o.X), min(b.Y, o.Y)
+	x1, y1 := max(b.X+b.Width, o.X+o.Width), max(b.Y+b.Height, o.Y+o.Height)
+	return Box{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Word is a run of glyphs without spaces
+type Word struct {
+	Text string
+	Box  Box
+}
+
+// Line is a run of words on the same baseline
+type Line struct {
+	Words []Word
+	Box   Box
+}
+
+// Text joins words of the line with spaces
+func (l Line) Text() string {
+	words := make([]string, len(l.Words))
+	for i, w := range l.Words {
+		words[i] = w.Text
+	}
+	return strings.Join(words, " ")
+}
+
+// glyph is a character placed on the page
+type glyph struct {
+	text string
+	// m is glyph transform a, b, c, d, e, f
+	m     [6]float64
+	width float64
+}
+
+// box maps glyph cell into page points
+func (g glyph) box() Box {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [][2]float64{{0, -descent}, {g.width, -descent}, {0, ascent}, {g.width, ascent}} {
+		x := g.m[0]*p[0] + g.m[2]*p[1] + g.m[4]
+		y := g.m[1]*p[0] + g.m[3]*p[1] + g.m[5]
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+	return Box{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+// size is font size in points
+func (g glyph) size() float64 {
+	return math.Hypot(g.m[0], g.m[1])
+}
+
+// Lines reads text layer of a page with positions of words. Positions come from glyphs
+// of the SVG output, glyph width is taken from its outline and height from font size
+func Lines(doc *fitz.Document, pageNum int) ([]Line, error) {
+	svg, err := doc.SVG(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	return parse(svg), nil
+}
+
+// parse groups glyphs of SVG page into words and lines
+func parse(svg string) []Line {
+	widths := make(map[string]float64)
+	for _, m := range outlineRe.FindAllStringSubmatch(svg, -1) {
+		widths[m[1]] = outlineWidth(m[2])
+	}
+
+	var lines []Line
+	var line Line
+	var word []glyph
+	var prev *glyph
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		w := Word{}
+		for _, g := range word {
+			w.Text += g.text
+			w.Box = w.Box.Union(g.box())
+		}
+		line.Words = append(line.Words, w)
+		line.Box = line.Box.Union(w.Box)
+		word = nil
+	}
+	flushLine := func() {
+		flushWord()
+		if len(line.Words) > 0 {
+			lines = append(lines, line)
+		}
+		line = Line{}
+	}
+
+	for _, m := range useRe.FindAllStringSubmatch(svg, -1) {
+		g := glyph{text: html.UnescapeString(m[1])}
+		values := strings.Split(m[3], ",")
+		if len(values) != len(g.m) {
+			continue
+		}
+		for i, v := range values {
+			g.m[i], _ = strconv.ParseFloat(strings.TrimSpace(v), 64)
+		}
+		g.width = widths[m[2]]
+		space := strings.TrimFunc(g.text, unicode.IsSpace) == ""
+		if g.width == 0 && !space {
+			g.width = defaultWidth
+		}
+
+		if prev != nil {
+			switch newLine, newWord := breaks(*prev, g); {
+			case newLine:
+				flushLine()
+			case newWord:
+				flushWord()
+			}
+		}
+		if space {
+			flushWord()
+		} else {
+			word = append(word, g)
+		}
+		prev = &g
+	}
+	flushLine()
+	return lines
+}
+
+// breaks tells if glyph g starts a new line or a new word after glyph prev
+func breaks(prev, g glyph) (newLine, newWord bool) {
+	size := prev.size()
+	if size == 0 {
+		return true, true
+	}
+	// position of g in the direction of prev baseline
+	dirX, dirY := prev.m[0]/size, prev.m[1]/size
+	dx, dy := g.m[4]-prev.m[4], g.m[5]-prev.m[5]
+	along := dx*dirX + dy*dirY
+	across := dy*dirX - dx*dirY
+
+	if math.Abs(across) > lineShift*size || along < -wordGap*size ||
+		math.Abs(g.m[0]*dirY-g.m[1]*dirX) > lineShift*g.size() {
+		return true, true
+	}
+	return false, along > (prev.width+wordGap)*size
+}
+
+// outlineWidth returns right edge of glyph outline in em units, path uses absolute commands
+func outlineWidth(d string) float64 {
+	width := 0.0
+	command := ""
+	// index of the next number within coordinate pair, H takes only x and V only y
+	coordinate := 0
+	for _, token := range commandRe.FindAllString(d, -1) {
+		if !numberRe.MatchString(token) {
+			command, coordinate = token, 0
+			continue
+		}
+		v, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			continue
+		}
+		switch command {
+		case "H":
+			width = max(width, v)
+		case "V":
+		default:
+			if coordinate%2 == 0 {
+				width = max(width, v)
+			}
+			coordinate++
+		}
+	}
+	return width
+}
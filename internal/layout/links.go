@@ -0,0 +1,132 @@
+package layout
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
+	"github.com/gen2brain/go-fitz"
+)
+
+// internal links of go-fitz are #page=N with optional view parameters
+var internalRe = regexp.MustCompile(`^#page=(\d+)`)
+
+// Link is a hyperlink of a page
+type Link struct {
+	URI string
+	// Page is one-based target page of internal links, 0 for external ones
+	Page int
+	// Box is area of the link, Found is false when it is unknown
+	Box   Box
+	Found bool
+	// Estimated is set when the area is guessed from the link text instead of taken from the annotation
+	Estimated bool
+}
+
+// AnnotLinks returns hyperlinks of a page with areas of their annotations
+func AnnotLinks(r *pdf.Reader, pageNum int) ([]Link, error) {
+	annots, err := r.Links(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	box, rotate, err := r.PageBox(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]Link, 0, len(annots))
+	for _, a := range annots {
+		link := Link{URI: a.URI, Page: a.Page, Box: pageBox(a.Rect, box, rotate), Found: true}
+		// internal links keep the form go-fitz gives them
+		if link.Page > 0 {
+			link.URI = "#page=" + strconv.Itoa(link.Page)
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// pageBox maps rectangle in PDF user space to the page as rendered: visible box turned
+// clockwise by rotation in degrees, with origin at its top left corner
+func pageBox(rect, box pdf.Rect, rotate int) Box {
+	r := rect.Rendered(box, rotate)
+	return Box{X: r.X0, Y: r.Y0, Width: r.X1 - r.X0, Height: r.Y1 - r.Y0}
+}
+
+// LocateLinks returns hyperlinks of a page for documents without readable annotations.
+// go-fitz doesn't expose link rectangles, so areas are estimated by looking for the URI
+// in page lines, Found is false when it isn't shown on the page
+func LocateLinks(doc *fitz.Document, pageNum int, lines []Line) ([]Link, error) {
+	fitzLinks, err := doc.Links(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[[2]int]bool)
+	links := make([]Link, 0, len(fitzLinks))
+	for _, l := range fitzLinks {
+		link := Link{URI: l.URI}
+		if m := internalRe.FindStringSubmatch(l.URI); m != nil {
+			link.Page, _ = strconv.Atoi(m[1])
+		} else {
+			link.Box, link.Found = locate(lines, l.URI, used)
+			link.Estimated = link.Found
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// locate finds the first unused occurrence of URI text in lines and returns area of its words.
+// Visible link text often omits scheme, www or trailing slash, so these forms are tried too
+func locate(lines []Line, uri string, used map[[2]int]bool) (Box, bool) {
+	for _, text := range displayForms(uri) {
+		for i, line := range lines {
+			lineText := line.Text()
+			for offset := 0; ; {
+				pos := strings.Index(lineText[offset:], text)
+				if pos < 0 {
+					break
+				}
+				start := offset + pos
+				offset = start + 1
+				if used[[2]int{i, start}] {
+					continue
+				}
+				used[[2]int{i, start}] = true
+				return wordsBox(line, start, start+len(text)), true
+			}
+		}
+	}
+	return Box{}, false
+}
+
+// wordsBox returns area of line words overlapping text from start to end byte
+func wordsBox(line Line, start, end int) Box {
+	var box Box
+	pos := 0
+	for _, w := range line.Words {
+		if pos < end && pos+len(w.Text) > start {
+			box = box.Union(w.Box)
+		}
+		pos += len(w.Text) + 1
+	}
+	return box
+}
+
+// displayForms lists texts a link can be shown with, longest first
+func displayForms(uri string) []string {
+	forms := []string{uri}
+	short := strings.TrimPrefix(uri, "mailto:")
+	for _, scheme := range []string{"https://", "http://"} {
+		short = strings.TrimPrefix(short, scheme)
+	}
+	short = strings.TrimPrefix(short, "www.")
+	for _, form := range []string{short, strings.TrimSuffix(short, "/")} {
+		if form != "" && form != forms[len(forms)-1] {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
@@ -0,0 +1,107 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/dmikhr/pdfjuicer/internal/pdf"
+)
+
+type pageBoxTestCase struct {
+	comment     string
+	rotate      int
+	expectedVal Box
+}
+
+// link at x 10-110, y 60-80 of visible box 0,50-300,500 which is 300x450 points
+var PageBoxTestCase = []pageBoxTestCase{
+	{comment: "Unrotated page", rotate: 0, expectedVal: Box{X: 10, Y: 420, Width: 100, Height: 20}},
+	{comment: "Page rotated by 90", rotate: 90, expectedVal: Box{X: 10, Y: 10, Width: 20, Height: 100}},
+	{comment: "Page rotated by 180", rotate: 180, expectedVal: Box{X: 190, Y: 10, Width: 100, Height: 20}},
+	{comment: "Page rotated by 270", rotate: 270, expectedVal: Box{X: 420, Y: 190, Width: 20, Height: 100}},
+}
+
+func TestPageBox(t *testing.T) {
+	rect := pdf.Rect{X0: 10, Y0: 60, X1: 110, Y1: 80}
+	box := pdf.Rect{X0: 0, Y0: 50, X1: 300, Y1: 500}
+	for _, tc := range PageBoxTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			if got := pageBox(rect, box, tc.rotate); got != tc.expectedVal {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+// line builds a line of words 10 points wide per character with 5 points gaps at top y
+func line(y float64, words ...string) Line {
+	var l Line
+	x := 0.0
+	for _, text := range words {
+		w := Word{Text: text, Box: Box{X: x, Y: y, Width: 10 * float64(len(text)), Height: 10}}
+		l.Words = append(l.Words, w)
+		l.Box = l.Box.Union(w.Box)
+		x += w.Box.Width + 5
+	}
+	return l
+}
+
+type locateTestCase struct {
+	comment string
+	lines   []Line
+	uris    []string
+	// expectedVal holds box of every URI in order, zero box when not found
+	expectedVal []Box
+}
+
+var LocateTestCase = []locateTestCase{
+	{
+		comment:     "URI shown in full among other words",
+		lines:       []Line{line(0, "see", "https://a.io/", "now")},
+		uris:        []string{"https://a.io/"},
+		expectedVal: []Box{{X: 35, Y: 0, Width: 130, Height: 10}},
+	},
+	{
+		comment:     "URI shown without scheme, www and trailing slash",
+		lines:       []Line{line(0, "visit", "a.io"), line(20, "write", "me@a.io")},
+		uris:        []string{"https://www.a.io/", "mailto:me@a.io"},
+		expectedVal: []Box{{X: 55, Y: 0, Width: 40, Height: 10}, {X: 55, Y: 20, Width: 70, Height: 10}},
+	},
+	{
+		comment:     "Repeated URI takes the next occurrence in the same line",
+		lines:       []Line{line(0, "a.io", "or", "a.io")},
+		uris:        []string{"a.io", "a.io"},
+		expectedVal: []Box{{X: 0, Y: 0, Width: 40, Height: 10}, {X: 70, Y: 0, Width: 40, Height: 10}},
+	},
+	{
+		comment:     "Repeated URI takes the next line",
+		lines:       []Line{line(0, "a.io"), line(20, "a.io")},
+		uris:        []string{"https://a.io", "https://a.io"},
+		expectedVal: []Box{{X: 0, Y: 0, Width: 40, Height: 10}, {X: 0, Y: 20, Width: 40, Height: 10}},
+	},
+	{
+		comment:     "URI is not found once all occurrences are used",
+		lines:       []Line{line(0, "a.io")},
+		uris:        []string{"a.io", "a.io"},
+		expectedVal: []Box{{X: 0, Y: 0, Width: 40, Height: 10}, {}},
+	},
+	{
+		comment:     "URI broken into words covers all of them",
+		lines:       []Line{line(0, "go", "to", "b.io/x", "y")},
+		uris:        []string{"to b.io/x"},
+		expectedVal: []Box{{X: 25, Y: 0, Width: 85, Height: 10}},
+	},
+}
+
+func TestLocate(t *testing.T) {
+	for _, tc := range LocateTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			used := make(map[[2]int]bool)
+			for i, uri := range tc.uris {
+				got, found := locate(tc.lines, uri, used)
+				if found != (tc.expectedVal[i] != Box{}) || got != tc.expectedVal[i] {
+					t.Errorf("%s test. link %d want: %v, got: %v (found: %t)", tc.comment, i+1, tc.expectedVal[i], got, found)
+				}
+			}
+		})
+	}
+}
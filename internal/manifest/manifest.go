@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"encoding/json"
+	"image"
+	"os"
+	"sort"
+	"sync"
+)
+
+// FileName is the name of manifest file saved into output folder
+const FileName = "manifest.json"
+
+// Manifest records per page results of a run, it is safe for concurrent use by workers.
+// Methods of a nil Manifest do nothing, so callers don't have to check if manifest is enabled
+type Manifest struct {
+	Source string `json:"source"`
+
+	mu    sync.Mutex
+	pages map[int]*PageEntry
+}
+
+// PageEntry describes outputs produced for a single page
+type PageEntry struct {
+	Page    int      `json:"page"`
+	Files   []string `json:"files,omitempty"`
+	CropBox *Box     `json:"cropBox,omitempty"`
+	Blank   bool     `json:"blank,omitempty"`
+	Hash    string   `json:"hash,omitempty"`
+	OCR     bool     `json:"ocr,omitempty"`
+	// DuplicateOf is number of the page kept instead of this one
+	DuplicateOf int `json:"duplicateOf,omitempty"`
+}
+
+// Box is a rectangle in pixels of the rendered page
+type Box struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// NewBox converts image rectangle into manifest box
+func NewBox(r image.Rectangle) *Box {
+	return &Box{X: r.Min.X, Y: r.Min.Y, Width: r.Dx(), Height: r.Dy()}
+}
+
+// Update applies fn to the entry of a page (zero-based number), creating entry if needed
+func (m *Manifest) Update(pageNum int, fn func(entry *PageEntry)) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pages == nil {
+		m.pages = make(map[int]*PageEntry)
+	}
+	entry, ok := m.pages[pageNum]
+	if !ok {
+		entry = &PageEntry{Page: pageNum + 1}
+		m.pages[pageNum] = entry
+	}
+	fn(entry)
+}
+
+// AddFile records file written for a page
+func (m *Manifest) AddFile(pageNum int, path string) {
+	m.Update(pageNum, func(entry *PageEntry) {
+		entry.Files = append(entry.Files, path)
+	})
+}
+
+// Save writes manifest with pages sorted by page number, since workers finish out of order
+func (m *Manifest) Save(path string) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pages := make([]*PageEntry, 0, len(m.pages))
+	for _, entry := range m.pages {
+		pages = append(pages, entry)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Page < pages[j].Page })
+
+	data, err := json.MarshalIndent(struct {
+		Source string       `json:"source"`
+		Pages  []*PageEntry `json:"pages"`
+	}{m.Source, pages}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
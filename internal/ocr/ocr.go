@@ -0,0 +1,56 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/dmikhr/pdfjuicer/internal/command"
+)
+
+// ErrNoOutput is returned when command finished without writing any of expected files
+var ErrNoOutput = errors.New("no recognized text files written")
+
+// Engine recognizes text on page images
+type Engine interface {
+	// Recognize reads image and writes recognized text into files named outBase
+	// with engine specific extensions, it returns paths of written files
+	Recognize(ctx context.Context, imagePath, outBase string) ([]string, error)
+}
+
+// Command is an engine running local tesseract-compatible CLI, for example
+// "tesseract {image} {output} txt hocr" which writes {output}.txt and {output}.hocr
+type Command struct {
+	Template command.Template
+	Timeout  time.Duration
+	// Extensions of files written by the command next to {output}
+	Extensions []string
+}
+
+// Recognize runs the command on a page image. Files of earlier runs are removed
+// first, so only files written by this run are returned
+func (c Command) Recognize(ctx context.Context, imagePath, outBase string) ([]string, error) {
+	for _, ext := range c.Extensions {
+		if err := os.Remove(outBase + ext); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	_, err := c.Template.Run(ctx, map[string]string{"{image}": imagePath, "{output}": outBase}, c.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, ext := range c.Extensions {
+		if _, err = os.Stat(outBase + ext); err == nil {
+			files = append(files, outBase+ext)
+		}
+	}
+	if len(files) == 0 {
+		return nil, ErrNoOutput
+	}
+	return files, nil
+}
@@ -0,0 +1,97 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dmikhr/pdfjuicer/internal/command"
+)
+
+type recognizeTestCase struct {
+	comment string
+	// script is run by sh with image and output paths as $1 and $2
+	script string
+	// stale files are left by an earlier run before recognition
+	stale         []string
+	expectedFiles []string
+	expectedErr   error
+}
+
+var RecognizeTestCase = []recognizeTestCase{
+	{
+		comment:       "Both files written",
+		script:        `echo "$1" > "$2.txt"; echo "<html/>" > "$2.hocr"`,
+		expectedFiles: []string{"page001.txt", "page001.hocr"},
+	},
+	{
+		comment:       "Stale file of earlier run is not returned",
+		script:        `echo "$1" > "$2.txt"`,
+		stale:         []string{"page001.hocr"},
+		expectedFiles: []string{"page001.txt"},
+	},
+	{
+		comment:     "Nothing written",
+		script:      `exit 0`,
+		stale:       []string{"page001.txt"},
+		expectedErr: ErrNoOutput,
+	},
+}
+
+func TestRecognize(t *testing.T) {
+	for _, tc := range RecognizeTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			dir := t.TempDir()
+			script := filepath.Join(dir, "ocr.sh")
+			if err := os.WriteFile(script, []byte(tc.script), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range tc.stale {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte("old"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			imagePath := filepath.Join(dir, "page001.png")
+			c := Command{
+				Template:   command.Template{Args: []string{"sh", script, "{image}", "{output}"}},
+				Extensions: []string{".txt", ".hocr"},
+			}
+
+			files, err := c.Recognize(context.Background(), imagePath, filepath.Join(dir, "page001"))
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("%s test. want error: %v, got: %v", tc.comment, tc.expectedErr, err)
+			}
+			if len(files) != len(tc.expectedFiles) {
+				t.Fatalf("%s test. want: %v, got: %v", tc.comment, tc.expectedFiles, files)
+			}
+			for i, f := range files {
+				if f != filepath.Join(dir, tc.expectedFiles[i]) {
+					t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expectedFiles, files)
+				}
+			}
+			// {image} is replaced with the image path and {output} with the path without extension
+			if len(files) > 0 {
+				text, err := os.ReadFile(files[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(text) != imagePath+"\n" {
+					t.Errorf("%s test. want image path: %s, got: %s", tc.comment, imagePath, text)
+				}
+			}
+		})
+	}
+}
+
+func TestRecognizeFailure(t *testing.T) {
+	c := Command{
+		Template:   command.Template{Args: []string{"sh", "-c", "echo broken image >&2; exit 3"}},
+		Extensions: []string{".txt"},
+	}
+	_, err := c.Recognize(context.Background(), "page001.png", filepath.Join(t.TempDir(), "page001"))
+	if err == nil || errors.Is(err, ErrNoOutput) {
+		t.Errorf("failure test. want command error, got: %v", err)
+	}
+}
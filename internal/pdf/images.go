@@ -0,0 +1,302 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// Matrix is an affine transformation [a b c d e f] mapping x, y to a*x+c*y+e, b*x+d*y+f
+type Matrix [6]float64
+
+// Identity is the transformation which keeps points in place
+var Identity = Matrix{1, 0, 0, 1, 0, 0}
+
+// Mul returns transformation applying m first and then n
+func (m Matrix) Mul(n Matrix) Matrix {
+	return Matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// Apply transforms a point
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+// Image is an image drawn on a page
+type Image struct {
+	// Ref of the image XObject, zero for inline images
+	Ref Ref
+	// Stream is the image dictionary with still encoded data. Abbreviations of inline
+	// images are expanded and their named color spaces are taken from resources
+	Stream Stream
+	// Rect is the area covered by the image in user space of the page
+	Rect Rect
+}
+
+// inlineKeys and inlineNames expand abbreviations of inline image dictionaries
+var (
+	inlineKeys = map[Name]Name{
+		"BPC": "BitsPerComponent", "CS": "ColorSpace", "D": "Decode", "DP": "DecodeParms",
+		"F": "Filter", "H": "Height", "IM": "ImageMask", "I": "Interpolate", "W": "Width", "L": "Length",
+	}
+	inlineNames = map[Name]Name{
+		"G": "DeviceGray", "RGB": "DeviceRGB", "CMYK": "DeviceCMYK", "I": "Indexed",
+		"AHx": "ASCIIHexDecode", "A85": "ASCII85Decode", "LZW": "LZWDecode", "Fl": "FlateDecode",
+		"RL": "RunLengthDecode", "CCF": "CCITTFaxDecode", "DCT": "DCTDecode",
+	}
+)
+
+// Images returns images drawn on the page in drawing order. Images of form XObjects are
+// included with their placement, images drawn several times are listed at every placement
+func (r *Reader) Images(pageNum int) ([]Image, error) {
+	if pageNum < 0 || pageNum >= len(r.pages) {
+		return nil, fmt.Errorf("page %d is out of range", pageNum+1)
+	}
+	p := r.pages[pageNum]
+	data, err := r.contents(p.dict["Contents"])
+	if err != nil {
+		return nil, err
+	}
+	s := &imageScanner{r: r, forms: make(map[Ref]bool), images: []Image{}}
+	s.scan(data, p.resources, Identity, 0)
+	return s.images, nil
+}
+
+// contents decodes content stream of a page, arrays of streams are joined
+func (r *Reader) contents(obj Object) ([]byte, error) {
+	switch v := r.Resolve(obj).(type) {
+	case Stream:
+		return r.decode(v)
+	case Array:
+		var buf bytes.Buffer
+		for _, item := range v {
+			stm, ok := r.Resolve(item).(Stream)
+			if !ok {
+				continue
+			}
+			data, err := r.decode(stm)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(data)
+			// tokens don't continue from one stream into the next
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, nil
+}
+
+// imageScanner follows graphics state of content streams collecting drawn images
+type imageScanner struct {
+	r      *Reader
+	images []Image
+	// forms holds form XObjects being drawn, a form drawing itself is skipped
+	forms map[Ref]bool
+}
+
+// scan interprets operators of content stream which affect image placement,
+// ctm maps user space of the stream to user space of the page
+func (s *imageScanner) scan(data []byte, resources Dict, ctm Matrix, depth int) {
+	p := &parser{data: data}
+	var stack []Matrix
+	var operands []Object
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return
+		}
+		start := p.pos
+		tok, err := p.token()
+		if err != nil {
+			// malformed tokens are skipped like readers do
+			p.pos = max(p.pos, start+1)
+			operands = operands[:0]
+			continue
+		}
+		op, ok := tok.(keyword)
+		if !ok || op == "true" || op == "false" || op == "null" {
+			if obj, err := p.objectFrom(tok); err == nil {
+				operands = append(operands, obj)
+			} else {
+				operands = operands[:0]
+			}
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if len(stack) > 0 {
+				ctm, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+		case "cm":
+			if m, ok := s.matrix(operands); ok {
+				ctm = m.Mul(ctm)
+			}
+		case "Do":
+			if len(operands) == 1 {
+				if name, ok := operands[0].(Name); ok {
+					s.draw(name, resources, ctm, depth)
+				}
+			}
+		case "BI":
+			if stm, ok := s.inlineImage(p, resources); ok {
+				s.images = append(s.images, Image{Stream: stm, Rect: unitRect(ctm)})
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// draw places image XObject or draws form XObject named in resources
+func (s *imageScanner) draw(name Name, resources Dict, ctm Matrix, depth int) {
+	xobjects, _ := s.r.Resolve(resources["XObject"]).(Dict)
+	obj := xobjects[name]
+	stm, ok := s.r.Resolve(obj).(Stream)
+	if !ok {
+		return
+	}
+	ref, _ := obj.(Ref)
+
+	switch s.r.Resolve(stm.Dict["Subtype"]) {
+	case Name("Image"):
+		s.images = append(s.images, Image{Ref: ref, Stream: stm, Rect: unitRect(ctm)})
+	case Name("Form"):
+		if depth >= maxDepth || ref != (Ref{}) && s.forms[ref] {
+			return
+		}
+		data, err := s.r.decode(stm)
+		if err != nil {
+			return
+		}
+		m, ok := s.matrix(s.r.Resolve(stm.Dict["Matrix"]))
+		if !ok {
+			m = Identity
+		}
+		// forms without resources use resources of the page drawing them
+		formResources, ok := s.r.Resolve(stm.Dict["Resources"]).(Dict)
+		if !ok {
+			formResources = resources
+		}
+		if ref != (Ref{}) {
+			s.forms[ref] = true
+			defer delete(s.forms, ref)
+		}
+		s.scan(data, formResources, m.Mul(ctm), depth+1)
+	}
+}
+
+// inlineImage reads BI ... ID data EI following BI operator
+func (s *imageScanner) inlineImage(p *parser, resources Dict) (Stream, bool) {
+	dict := Dict{}
+	for {
+		tok, err := p.token()
+		if err != nil {
+			return Stream{}, false
+		}
+		if tok == keyword("ID") {
+			break
+		}
+		key, ok := tok.(Name)
+		if !ok {
+			return Stream{}, false
+		}
+		value, err := p.object()
+		if err != nil {
+			return Stream{}, false
+		}
+		if full, ok := inlineKeys[key]; ok {
+			key = full
+		}
+		if key == "ColorSpace" || key == "Filter" {
+			value = s.expandName(value, resources)
+		}
+		dict[key] = value
+	}
+
+	// single whitespace separates ID from data, EI must be surrounded by whitespace
+	start := p.pos + 1
+	end := start
+	for {
+		i := bytes.Index(p.data[min(end, len(p.data)):], []byte("EI"))
+		if i < 0 {
+			return Stream{}, false
+		}
+		end += i
+		after := end + 2
+		if end > start && isSpace(p.data[end-1]) && (after == len(p.data) || isSpace(p.data[after])) {
+			break
+		}
+		end += 2
+	}
+	p.pos = end + 2
+	return Stream{Dict: dict, Data: p.data[start : end-1]}, true
+}
+
+// expandName expands abbreviated names of inline image filters and color spaces,
+// color spaces which aren't abbreviations are looked up in resources
+func (s *imageScanner) expandName(value Object, resources Dict) Object {
+	switch v := value.(type) {
+	case Name:
+		if full, ok := inlineNames[v]; ok {
+			return full
+		}
+		spaces, _ := s.r.Resolve(resources["ColorSpace"]).(Dict)
+		if cs, ok := spaces[v]; ok {
+			return s.r.Resolve(cs)
+		}
+	case Array:
+		expanded := make(Array, len(v))
+		for i, item := range v {
+			expanded[i] = item
+			if name, ok := item.(Name); ok {
+				if full, ok := inlineNames[name]; ok {
+					expanded[i] = full
+				}
+			}
+		}
+		return expanded
+	}
+	return value
+}
+
+// matrix reads six numbers of operands or a matrix array
+func (s *imageScanner) matrix(obj any) (Matrix, bool) {
+	var items []Object
+	switch v := obj.(type) {
+	case []Object:
+		items = v
+	case Array:
+		items = v
+	}
+	var m Matrix
+	if len(items) != len(m) {
+		return m, false
+	}
+	for i, item := range items {
+		var ok bool
+		if m[i], ok = s.r.number(item); !ok {
+			return m, false
+		}
+	}
+	return m, true
+}
+
+// unitRect returns area covered by unit square, which images are drawn into, after transformation
+func unitRect(m Matrix) Rect {
+	rect := Rect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, corner := range [][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		x, y := m.Apply(corner[0], corner[1])
+		rect = Rect{min(rect.X0, x), min(rect.Y0, y), max(rect.X1, x), max(rect.Y1, y)}
+	}
+	return rect
+}
@@ -0,0 +1,151 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// stream makes stream object with entries of dict and data
+func stream(dict, data string) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+// imagePages is a document with one page inheriting resources from pages node, image is
+// object 5, form drawing it is object 6 and object 7 may be another form
+func imagePages(content string, extra ...string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 400 500] " +
+			"/Resources << /XObject << /Im1 5 0 R /Fm1 6 0 R /Fm2 7 0 R >> /ColorSpace << /CS0 /DeviceRGB >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		stream("/Filter /FlateDecode", string(deflate([]byte(content)))),
+		stream("/Type /XObject /Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8", "\x00\xff"),
+		stream("/Type /XObject /Subtype /Form /BBox [0 0 1 1] /Matrix [2 0 0 2 10 10]", "20 0 0 10 0 0 cm /Im1 Do"),
+	}
+	return buildPDF("", append(objects, extra...)...)
+}
+
+type imagesTestCase struct {
+	comment     string
+	data        []byte
+	expectedVal []Rect
+	// refs are object numbers of images, 0 for inline ones
+	refs []int
+}
+
+var ImagesTestCase = []imagesTestCase{
+	{
+		comment:     "Scaled and moved image",
+		data:        imagePages("q 100 0 0 50 10 20 cm /Im1 Do Q"),
+		expectedVal: []Rect{{10, 20, 110, 70}},
+		refs:        []int{5},
+	},
+	{
+		comment:     "Graphics state restored between placements",
+		data:        imagePages("q 1 0 0 1 100 100 cm q 10 0 0 10 0 0 cm /Im1 Do Q 20 0 0 20 0 0 cm /Im1 Do Q 5 0 0 5 0 0 cm /Im1 Do"),
+		expectedVal: []Rect{{100, 100, 110, 110}, {100, 100, 120, 120}, {0, 0, 5, 5}},
+		refs:        []int{5, 5, 5},
+	},
+	{
+		comment:     "Rotated image",
+		data:        imagePages("0 30 -40 0 100 100 cm /Im1 Do"),
+		expectedVal: []Rect{{60, 100, 100, 130}},
+		refs:        []int{5},
+	},
+	{
+		comment:     "Image inside form with its matrix",
+		data:        imagePages("1 0 0 1 100 100 cm /Fm1 Do"),
+		expectedVal: []Rect{{110, 110, 150, 130}},
+		refs:        []int{5},
+	},
+	{
+		comment:     "Inline image among text operators",
+		data:        imagePages("BT /F1 12 Tf (EI) Tj ET q 30 0 0 20 5 5 cm BI /W 2 /H 1 /CS /G /BPC 8 ID \x00EI\xff EI Q /Im1 Do"),
+		expectedVal: []Rect{{5, 5, 35, 25}, {0, 0, 1, 1}},
+		refs:        []int{0, 5},
+	},
+	{
+		comment:     "Unknown resource and form drawing itself",
+		data:        imagePages("/Im9 Do /Fm2 Do", stream("/Subtype /Form /Resources << /XObject << /Fm2 7 0 R >> >>", "/Fm2 Do")),
+		expectedVal: []Rect{},
+		refs:        []int{},
+	},
+}
+
+func TestImages(t *testing.T) {
+	for _, tc := range ImagesTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			r, err := NewReader(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			images, err := r.Images(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rects, refs := []Rect{}, []int{}
+			for _, img := range images {
+				rects = append(rects, img.Rect)
+				refs = append(refs, img.Ref.Num)
+			}
+			if fmt.Sprint(rects) != fmt.Sprint(tc.expectedVal) || fmt.Sprint(refs) != fmt.Sprint(tc.refs) {
+				t.Errorf("%s test. want: %v %v, got: %v %v", tc.comment, tc.expectedVal, tc.refs, rects, refs)
+			}
+		})
+	}
+}
+
+func TestInlineImage(t *testing.T) {
+	r, err := NewReader(imagePages("BI /W 2 /H 1 /CS /CS0 /BPC 8 /F /AHx ID 00ff00ffffff> EI"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	images, err := r.Images(0)
+	if err != nil || len(images) != 1 {
+		t.Fatalf("inline image test. want one image, got: %v %v", images, err)
+	}
+	dict := images[0].Stream.Dict
+	if dict["Width"] != 2 || dict["ColorSpace"] != Name("DeviceRGB") || dict["Filter"] != Name("ASCIIHexDecode") {
+		t.Errorf("inline image test. want expanded dictionary, got: %v", dict)
+	}
+	data, filter, err := r.Decode(images[0].Stream)
+	if err != nil || filter != "" || !bytes.Equal(data, []byte{0, 0xff, 0, 0xff, 0xff, 0xff}) {
+		t.Errorf("inline image test. want decoded data, got: %v %q %v", data, filter, err)
+	}
+}
+
+type decodeTestCase struct {
+	comment        string
+	filter         string
+	data           string
+	expectedVal    string
+	expectedFilter Name
+}
+
+var DecodeTestCase = []decodeTestCase{
+	{comment: "ASCIIHex with odd digits", filter: "/ASCIIHexDecode", data: "48 65 6c6C 6>", expectedVal: "Hell`"},
+	{comment: "ASCII85", filter: "/ASCII85Decode", data: "<~87cURD]j7BEbo7~>", expectedVal: "Hello world"},
+	{comment: "RunLength", filter: "/RunLengthDecode", data: "\x01ab\xfdc\x80", expectedVal: "abcccc"},
+	{comment: "JPEG left encoded after ASCIIHex", filter: "[/ASCIIHexDecode /DCTDecode]", data: "ffd8>", expectedVal: "\xff\xd8", expectedFilter: "DCTDecode"},
+}
+
+func TestDecode(t *testing.T) {
+	r := &Reader{}
+	for _, tc := range DecodeTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			p := &parser{data: []byte(tc.filter)}
+			filter, err := p.object()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, name, err := r.Decode(Stream{Dict: Dict{"Filter": filter}, Data: []byte(tc.data)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tc.expectedVal || name != tc.expectedFilter {
+				t.Errorf("%s test. want: %q %q, got: %q %q", tc.comment, tc.expectedVal, tc.expectedFilter, data, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Object is a PDF object: nil, bool, int, float64, Name, String, Array, Dict, Ref or Stream
+type Object any
+
+// Name is a PDF name without leading slash
+type Name string
+
+// String is a PDF literal or hex string with escapes decoded
+type String string
+
+// Array is a PDF array
+type Array []Object
+
+// Dict is a PDF dictionary
+type Dict map[Name]Object
+
+// Ref is a reference to an indirect object
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Stream is a stream object with its raw, still encoded data
+type Stream struct {
+	Dict Dict
+	Data []byte
+}
+
+// errSyntax is returned for malformed objects
+var errSyntax = errors.New("pdf syntax error")
+
+// keyword is a bare word token like obj, R, true or stream
+type keyword string
+
+// parser reads objects from PDF data starting at pos
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and comments
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isSpace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+// token returns next token: delimiter string like << or [, Name, String, number or keyword
+func (p *parser) token() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", errSyntax)
+	}
+	c := p.data[p.pos]
+	switch c {
+	case '[', ']', '{', '}':
+		p.pos++
+		return string(c), nil
+	case '<':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '<' {
+			p.pos += 2
+			return "<<", nil
+		}
+		return p.hexString()
+	case '>':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			return ">>", nil
+		}
+		return nil, fmt.Errorf("%w: unexpected > at %d", errSyntax, p.pos)
+	case '(':
+		return p.literalString()
+	case '/':
+		return p.name(), nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.data) && !isSpace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	word := string(p.data[start:p.pos])
+	if word == "" {
+		p.pos++
+		return nil, fmt.Errorf("%w: unexpected %q at %d", errSyntax, c, start)
+	}
+	if i, err := strconv.Atoi(word); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, nil
+	}
+	return keyword(word), nil
+}
+
+// name reads /Name decoding #xx escapes
+func (p *parser) name() Name {
+	p.pos++
+	var b []byte
+	for p.pos < len(p.data) && !isSpace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		c := p.data[p.pos]
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		p.pos++
+	}
+	return Name(b)
+}
+
+// literalString reads (string) with nested parentheses and escapes
+func (p *parser) literalString() (String, error) {
+	p.pos++
+	var b []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(b), nil
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				continue
+			}
+			e := p.data[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// line continuation
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return "", fmt.Errorf("%w: unterminated string", errSyntax)
+}
+
+// hexString reads <hex> string, odd number of digits is padded with 0
+func (p *parser) hexString() (String, error) {
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end < 0 {
+		return "", fmt.Errorf("%w: unterminated hex string", errSyntax)
+	}
+	var digits []byte
+	for _, c := range p.data[p.pos+1 : p.pos+end] {
+		if !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	p.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b := make([]byte, len(digits)/2)
+	for i := range b {
+		v, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("%w: bad hex string", errSyntax)
+		}
+		b[i] = byte(v)
+	}
+	return String(b), nil
+}
+
+// object reads a direct object, integers followed by generation and R become references
+func (p *parser) object() (Object, error) {
+	tok, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	return p.objectFrom(tok)
+}
+
+func (p *parser) objectFrom(tok any) (Object, error) {
+	switch t := tok.(type) {
+	case int:
+		// look ahead for "gen R" of a reference
+		save := p.pos
+		if gen, err := p.token(); err == nil {
+			if g, ok := gen.(int); ok {
+				if r, err := p.token(); err == nil && r == keyword("R") {
+					return Ref{Num: t, Gen: g}, nil
+				}
+			}
+		}
+		p.pos = save
+		return t, nil
+	case float64, Name, String:
+		return t, nil
+	case keyword:
+		switch t {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: unexpected keyword %s", errSyntax, t)
+	case string:
+		switch t {
+		case "[":
+			return p.array()
+		case "<<":
+			return p.dict()
+		}
+	}
+	return nil, fmt.Errorf("%w: unexpected token %v", errSyntax, tok)
+}
+
+func (p *parser) array() (Array, error) {
+	arr := Array{}
+	for {
+		tok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if tok == "]" {
+			return arr, nil
+		}
+		obj, err := p.objectFrom(tok)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, obj)
+	}
+}
+
+func (p *parser) dict() (Dict, error) {
+	d := Dict{}
+	for {
+		tok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if tok == ">>" {
+			return d, nil
+		}
+		key, ok := tok.(Name)
+		if !ok {
+			return nil, fmt.Errorf("%w: dictionary key %v is not a name", errSyntax, tok)
+		}
+		value, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+		d[key] = value
+	}
+}
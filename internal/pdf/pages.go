@@ -0,0 +1,266 @@
+package pdf
+
+import (
+	"fmt"
+)
+
+// Rect is a rectangle in PDF user space with lower left and upper right corners
+type Rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Link is a link annotation of a page
+type Link struct {
+	// URI of external links, empty for internal ones
+	URI string
+	// Page is one-based target page of internal links, 0 for external ones
+	Page int
+	Rect Rect
+}
+
+// page is a leaf of the page tree with inherited attributes resolved
+type page struct {
+	dict Dict
+	// box is visible area of the page: CropBox clipped by MediaBox
+	box    Rect
+	rotate int
+	// resources are own or inherited from parents
+	resources Dict
+}
+
+// NumPage returns number of pages
+func (r *Reader) NumPage() int {
+	return len(r.pages)
+}
+
+// PageBox returns visible area of the page and its clockwise rotation in degrees
+func (r *Reader) PageBox(pageNum int) (Rect, int, error) {
+	if pageNum < 0 || pageNum >= len(r.pages) {
+		return Rect{}, 0, fmt.Errorf("page %d is out of range", pageNum+1)
+	}
+	p := r.pages[pageNum]
+	return p.box, p.rotate, nil
+}
+
+// Rendered maps rectangle in user space to the page as rendered: visible box turned clockwise
+// by rotation in degrees, with origin at its top left corner. X0, Y0 is the top left corner
+func (rect Rect) Rendered(box Rect, rotate int) Rect {
+	// corners relative to the top left corner of the unrotated page
+	x0, y0 := rect.X0-box.X0, box.Y1-rect.Y1
+	x1, y1 := rect.X1-box.X0, box.Y1-rect.Y0
+	w, h := box.X1-box.X0, box.Y1-box.Y0
+	switch rotate {
+	case 90:
+		x0, y0, x1, y1 = h-y1, x0, h-y0, x1
+	case 180:
+		x0, y0, x1, y1 = w-x1, h-y1, w-x0, h-y0
+	case 270:
+		x0, y0, x1, y1 = y0, w-x1, y1, w-x0
+	}
+	return Rect{x0, y0, x1, y1}
+}
+
+// loadPages walks the page tree collecting pages in order
+func (r *Reader) loadPages() error {
+	root, ok := r.Resolve(r.trailer["Root"]).(Dict)
+	if !ok {
+		return ErrNoRoot
+	}
+	r.byRef = make(map[Ref]int)
+	r.walk(root["Pages"], Dict{}, make(map[Ref]bool))
+	if len(r.pages) == 0 {
+		return fmt.Errorf("%w: no pages found", errSyntax)
+	}
+	return nil
+}
+
+// walk adds pages of the tree node, inherited holds MediaBox, CropBox, Rotate and Resources of parents
+func (r *Reader) walk(node Object, inherited Dict, visited map[Ref]bool) {
+	if ref, ok := node.(Ref); ok {
+		if visited[ref] {
+			return
+		}
+		visited[ref] = true
+	}
+	dict, ok := r.Resolve(node).(Dict)
+	if !ok {
+		return
+	}
+
+	attrs := Dict{}
+	for _, key := range []Name{"MediaBox", "CropBox", "Rotate", "Resources"} {
+		attrs[key] = inherited[key]
+		if v, ok := dict[key]; ok {
+			attrs[key] = v
+		}
+	}
+
+	if kids, ok := r.Resolve(dict["Kids"]).(Array); ok && dict["Type"] != Name("Page") {
+		for _, kid := range kids {
+			r.walk(kid, attrs, visited)
+		}
+		return
+	}
+
+	media, ok := r.rect(attrs["MediaBox"])
+	if !ok {
+		// US Letter is the default of most readers for broken files
+		media = Rect{0, 0, 612, 792}
+	}
+	box := media
+	if crop, ok := r.rect(attrs["CropBox"]); ok {
+		box = Rect{max(crop.X0, media.X0), max(crop.Y0, media.Y0), min(crop.X1, media.X1), min(crop.Y1, media.Y1)}
+		if box.X1 <= box.X0 || box.Y1 <= box.Y0 {
+			box = media
+		}
+	}
+	rotate, _ := r.number(attrs["Rotate"])
+	resources, _ := r.Resolve(attrs["Resources"]).(Dict)
+
+	if ref, ok := node.(Ref); ok {
+		r.byRef[ref] = len(r.pages)
+	}
+	r.pages = append(r.pages, page{dict: dict, box: box, rotate: ((int(rotate)/90)%4 + 4) % 4 * 90, resources: resources})
+}
+
+// number returns numeric value of the object
+func (r *Reader) number(obj Object) (float64, bool) {
+	switch v := r.Resolve(obj).(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// rect reads rectangle array normalizing its corners
+func (r *Reader) rect(obj Object) (Rect, bool) {
+	arr, ok := r.Resolve(obj).(Array)
+	if !ok || len(arr) != 4 {
+		return Rect{}, false
+	}
+	var v [4]float64
+	for i, item := range arr {
+		if v[i], ok = r.number(item); !ok {
+			return Rect{}, false
+		}
+	}
+	return Rect{min(v[0], v[2]), min(v[1], v[3]), max(v[0], v[2]), max(v[1], v[3])}, true
+}
+
+// Links returns URI and GoTo link annotations of the page, other actions and
+// destinations which don't resolve to a page of the document are left out
+func (r *Reader) Links(pageNum int) ([]Link, error) {
+	if pageNum < 0 || pageNum >= len(r.pages) {
+		return nil, fmt.Errorf("page %d is out of range", pageNum+1)
+	}
+	annots, _ := r.Resolve(r.pages[pageNum].dict["Annots"]).(Array)
+
+	links := []Link{}
+	for _, a := range annots {
+		annot, ok := r.Resolve(a).(Dict)
+		if !ok || r.Resolve(annot["Subtype"]) != Name("Link") {
+			continue
+		}
+		rect, ok := r.rect(annot["Rect"])
+		if !ok {
+			continue
+		}
+		link := Link{Rect: rect}
+
+		if action, ok := r.Resolve(annot["A"]).(Dict); ok {
+			switch r.Resolve(action["S"]) {
+			case Name("URI"):
+				uri, _ := r.Resolve(action["URI"]).(String)
+				link.URI = string(uri)
+			case Name("GoTo"):
+				link.Page = r.destPage(action["D"])
+			}
+		} else if dest, ok := annot["Dest"]; ok {
+			link.Page = r.destPage(dest)
+		}
+		if link.URI != "" || link.Page > 0 {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// destPage returns one-based page of explicit or named destination, 0 if not found
+func (r *Reader) destPage(dest Object) int {
+	for depth := 0; depth < maxDepth; depth++ {
+		switch d := r.Resolve(dest).(type) {
+		case Array:
+			if len(d) == 0 {
+				return 0
+			}
+			if ref, ok := d[0].(Ref); ok {
+				if i, ok := r.byRef[ref]; ok {
+					return i + 1
+				}
+				return 0
+			}
+			// remote destinations use page numbers instead of references
+			if i, ok := d[0].(int); ok && i >= 0 && i < len(r.pages) {
+				return i + 1
+			}
+			return 0
+		case Dict:
+			dest = d["D"]
+		case Name:
+			dest = r.namedDest(string(d))
+		case String:
+			dest = r.namedDest(string(d))
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+// namedDest looks up destination in the catalog Dests dictionary and Names tree
+func (r *Reader) namedDest(name string) Object {
+	root, _ := r.Resolve(r.trailer["Root"]).(Dict)
+	if dests, ok := r.Resolve(root["Dests"]).(Dict); ok {
+		if dest, ok := dests[Name(name)]; ok {
+			return dest
+		}
+	}
+	names, _ := r.Resolve(root["Names"]).(Dict)
+	return r.lookupName(names["Dests"], name, 0)
+}
+
+// lookupName finds value of the key in a name tree
+func (r *Reader) lookupName(node Object, key string, depth int) Object {
+	dict, ok := r.Resolve(node).(Dict)
+	if !ok || depth > maxDepth {
+		return nil
+	}
+	if names, ok := r.Resolve(dict["Names"]).(Array); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			if k, ok := r.Resolve(names[i]).(String); ok && string(k) == key {
+				return names[i+1]
+			}
+		}
+	}
+	kids, _ := r.Resolve(dict["Kids"]).(Array)
+	for _, kid := range kids {
+		kidDict, ok := r.Resolve(kid).(Dict)
+		if !ok {
+			continue
+		}
+		// Limits are optional, kids without them are searched
+		if limits, ok := r.Resolve(kidDict["Limits"]).(Array); ok && len(limits) == 2 {
+			lo, _ := r.Resolve(limits[0]).(String)
+			hi, _ := r.Resolve(limits[1]).(String)
+			if key < string(lo) || key > string(hi) {
+				continue
+			}
+		}
+		if v := r.lookupName(kid, key, depth+1); v != nil {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,638 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+var (
+	// ErrEncrypted is returned for encrypted documents, their strings can't be read without decryption
+	ErrEncrypted = errors.New("encrypted pdf")
+	// ErrNoRoot is returned when document catalog is not found
+	ErrNoRoot = errors.New("pdf catalog not found")
+)
+
+// maxDepth limits nesting of references followed while resolving objects
+const maxDepth = 32
+
+// xrefEntry locates an object: at offset in the file or at index inside an object stream
+type xrefEntry struct {
+	offset int
+	stream int
+	index  int
+	inStm  bool
+}
+
+// Reader gives access to objects of a PDF file which go-fitz doesn't expose.
+// It is safe for concurrent use
+type Reader struct {
+	data    []byte
+	xref    map[int]xrefEntry
+	trailer Dict
+
+	// pages and their indexes by reference are read once on open
+	pages []page
+	byRef map[Ref]int
+
+	mu      sync.Mutex
+	objStms map[int]*objStm
+}
+
+// objStm is a decoded object stream with offsets of its objects
+type objStm struct {
+	data    []byte
+	offsets map[int]int
+}
+
+// Open reads PDF file and its cross-reference table
+func Open(path string) (*Reader, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReader(data)
+}
+
+// NewReader parses cross-reference table of PDF data, broken tables are rebuilt by scanning objects
+func NewReader(data []byte) (*Reader, error) {
+	r := &Reader{data: data, xref: make(map[int]xrefEntry), objStms: make(map[int]*objStm)}
+	if err := r.readXref(); err != nil || r.trailer["Root"] == nil {
+		r.xref = make(map[int]xrefEntry)
+		r.trailer = nil
+		if err = r.repair(); err != nil {
+			return nil, err
+		}
+	}
+	if r.trailer["Encrypt"] != nil {
+		return nil, ErrEncrypted
+	}
+	if err := r.loadPages(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+var startxrefRe = regexp.MustCompile(`startxref\s+(\d+)`)
+
+// readXref follows the chain of cross-reference sections from the last startxref
+func (r *Reader) readXref() error {
+	tail := r.data[max(len(r.data)-1024, 0):]
+	matches := startxrefRe.FindAllSubmatch(tail, -1)
+	if len(matches) == 0 {
+		return fmt.Errorf("%w: startxref not found", errSyntax)
+	}
+	offset, _ := strconv.Atoi(string(matches[len(matches)-1][1]))
+
+	visited := make(map[int]bool)
+	for offset > 0 && !visited[offset] {
+		visited[offset] = true
+		trailer, err := r.readSection(offset)
+		if err != nil {
+			return err
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+		}
+		// hybrid files keep compressed objects in a separate stream
+		if stm, ok := trailer["XRefStm"].(int); ok && !visited[stm] {
+			visited[stm] = true
+			if _, err = r.readSection(stm); err != nil {
+				return err
+			}
+		}
+		offset, _ = trailer["Prev"].(int)
+	}
+	return nil
+}
+
+// readSection reads xref table or xref stream at offset, entries of newer sections read earlier win
+func (r *Reader) readSection(offset int) (Dict, error) {
+	if offset >= len(r.data) {
+		return nil, fmt.Errorf("%w: xref offset out of file", errSyntax)
+	}
+	p := &parser{data: r.data, pos: offset}
+	tok, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == keyword("xref") {
+		return r.readTable(p)
+	}
+
+	obj, err := r.objectAt(offset)
+	if err != nil {
+		return nil, err
+	}
+	stm, ok := obj.(Stream)
+	if !ok || stm.Dict["Type"] != Name("XRef") {
+		return nil, fmt.Errorf("%w: no xref at %d", errSyntax, offset)
+	}
+	return stm.Dict, r.readStream(stm)
+}
+
+// readTable reads classic xref table and the trailer following it
+func (r *Reader) readTable(p *parser) (Dict, error) {
+	for {
+		tok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if tok == keyword("trailer") {
+			return p.expectDict()
+		}
+		start, ok := tok.(int)
+		if !ok {
+			return nil, fmt.Errorf("%w: bad xref subsection", errSyntax)
+		}
+		countTok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		count, ok := countTok.(int)
+		if !ok {
+			return nil, fmt.Errorf("%w: bad xref subsection", errSyntax)
+		}
+		for i := 0; i < count; i++ {
+			offsetTok, _ := p.token()
+			_, _ = p.token()
+			kind, err := p.token()
+			if err != nil {
+				return nil, err
+			}
+			offset, _ := offsetTok.(int)
+			if _, seen := r.xref[start+i]; !seen && kind == keyword("n") {
+				r.xref[start+i] = xrefEntry{offset: offset}
+			} else if !seen && kind == keyword("f") {
+				r.xref[start+i] = xrefEntry{offset: -1}
+			}
+		}
+	}
+}
+
+// expectDict reads dictionary which must start at the current position
+func (p *parser) expectDict() (Dict, error) {
+	tok, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != "<<" {
+		return nil, fmt.Errorf("%w: dictionary expected", errSyntax)
+	}
+	return p.dict()
+}
+
+// readStream reads entries of a cross-reference stream
+func (r *Reader) readStream(stm Stream) error {
+	data, err := r.decode(stm)
+	if err != nil {
+		return err
+	}
+	w, _ := stm.Dict["W"].(Array)
+	if len(w) != 3 {
+		return fmt.Errorf("%w: bad xref stream widths", errSyntax)
+	}
+	widths := make([]int, 3)
+	for i, v := range w {
+		widths[i], _ = v.(int)
+	}
+	index := Array{0, stm.Dict["Size"]}
+	if idx, ok := stm.Dict["Index"].(Array); ok {
+		index = idx
+	}
+
+	entrySize := widths[0] + widths[1] + widths[2]
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, _ := index[i].(int)
+		count, _ := index[i+1].(int)
+		for j := 0; j < count && pos+entrySize <= len(data); j++ {
+			fields := make([]int, 3)
+			for k, width := range widths {
+				for n := 0; n < width; n++ {
+					fields[k] = fields[k]<<8 | int(data[pos])
+					pos++
+				}
+			}
+			// type field is 1 when its width is 0
+			if widths[0] == 0 {
+				fields[0] = 1
+			}
+			if _, seen := r.xref[start+j]; seen {
+				continue
+			}
+			switch fields[0] {
+			case 0:
+				r.xref[start+j] = xrefEntry{offset: -1}
+			case 1:
+				r.xref[start+j] = xrefEntry{offset: fields[1]}
+			case 2:
+				r.xref[start+j] = xrefEntry{stream: fields[1], index: fields[2], inStm: true}
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	objRe     = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	trailerRe = regexp.MustCompile(`trailer\s*<<`)
+)
+
+// repair rebuilds cross-reference table by scanning the file for objects, later objects win.
+// Trailer is taken from the last trailer dictionary or xref stream
+func (r *Reader) repair() error {
+	for _, m := range objRe.FindAllSubmatchIndex(r.data, -1) {
+		num, _ := strconv.Atoi(string(r.data[m[2]:m[3]]))
+		r.xref[num] = xrefEntry{offset: m[0]}
+	}
+	for _, i := range trailerRe.FindAllIndex(r.data, -1) {
+		p := &parser{data: r.data, pos: i[0] + len("trailer")}
+		if trailer, err := p.expectDict(); err == nil && trailer["Root"] != nil {
+			r.trailer = trailer
+		}
+	}
+	for num, entry := range r.xref {
+		obj, err := r.objectAt(entry.offset)
+		if stm, ok := obj.(Stream); err == nil && ok && stm.Dict["Type"] == Name("XRef") {
+			if r.trailer == nil {
+				r.trailer = stm.Dict
+			}
+			// objects of object streams are listed in xref streams only
+			_ = r.readStream(stm)
+			r.xref[num] = entry
+		}
+	}
+	for num, entry := range r.xref {
+		if entry.inStm {
+			continue
+		}
+		if obj, err := r.objectAt(entry.offset); err == nil {
+			if stm, ok := obj.(Stream); ok && stm.Dict["Type"] == Name("ObjStm") {
+				r.addObjStm(num)
+			}
+		}
+	}
+	if r.trailer == nil || r.trailer["Root"] == nil {
+		return ErrNoRoot
+	}
+	return nil
+}
+
+// addObjStm lists objects of an object stream which are missing in xref
+func (r *Reader) addObjStm(num int) {
+	stm, err := r.objStm(num)
+	if err != nil {
+		return
+	}
+	for objNum := range stm.offsets {
+		if _, ok := r.xref[objNum]; !ok {
+			r.xref[objNum] = xrefEntry{stream: num, inStm: true}
+		}
+	}
+}
+
+// objectAt reads indirect object "num gen obj ... endobj" at offset
+func (r *Reader) objectAt(offset int) (Object, error) {
+	if offset < 0 || offset >= len(r.data) {
+		return nil, fmt.Errorf("%w: object offset out of file", errSyntax)
+	}
+	p := &parser{data: r.data, pos: offset}
+	for i := 0; i < 3; i++ {
+		if _, err := p.token(); err != nil {
+			return nil, err
+		}
+	}
+	obj, err := p.object()
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := obj.(Dict)
+	if !ok {
+		return obj, nil
+	}
+
+	save := p.pos
+	if tok, err := p.token(); err != nil || tok != keyword("stream") {
+		p.pos = save
+		return obj, nil
+	}
+	// stream data starts after end of line following the keyword
+	if p.pos < len(r.data) && r.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(r.data) && r.data[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+	length, ok := r.resolveDepth(dict["Length"], 1).(int)
+	end := start + length
+	if !ok || end > len(r.data) || !bytes.HasPrefix(bytes.TrimLeft(r.data[end:min(end+32, len(r.data))], "\r\n \t"), []byte("endstream")) {
+		i := bytes.Index(r.data[start:], []byte("endstream"))
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unterminated stream", errSyntax)
+		}
+		end = start + i
+	}
+	return Stream{Dict: dict, Data: r.data[start:end]}, nil
+}
+
+// Resolve follows references and returns direct object, missing objects are nil
+func (r *Reader) Resolve(obj Object) Object {
+	return r.resolveDepth(obj, 0)
+}
+
+func (r *Reader) resolveDepth(obj Object, depth int) Object {
+	ref, ok := obj.(Ref)
+	if !ok {
+		return obj
+	}
+	if depth > maxDepth {
+		return nil
+	}
+	entry, ok := r.xref[ref.Num]
+	if !ok || entry.offset < 0 && !entry.inStm {
+		return nil
+	}
+
+	var resolved Object
+	var err error
+	if entry.inStm {
+		resolved, err = r.objectInStm(entry.stream, ref.Num)
+	} else {
+		resolved, err = r.objectAt(entry.offset)
+	}
+	if err != nil {
+		return nil
+	}
+	return r.resolveDepth(resolved, depth+1)
+}
+
+// objectInStm reads object num from object stream
+func (r *Reader) objectInStm(stmNum, num int) (Object, error) {
+	stm, err := r.objStm(stmNum)
+	if err != nil {
+		return nil, err
+	}
+	offset, ok := stm.offsets[num]
+	if !ok {
+		return nil, fmt.Errorf("%w: object %d not in stream %d", errSyntax, num, stmNum)
+	}
+	p := &parser{data: stm.data, pos: offset}
+	return p.object()
+}
+
+// objStm decodes object stream once and caches it
+func (r *Reader) objStm(num int) (*objStm, error) {
+	r.mu.Lock()
+	stm, ok := r.objStms[num]
+	r.mu.Unlock()
+	if ok {
+		return stm, nil
+	}
+
+	entry, ok := r.xref[num]
+	if !ok || entry.inStm {
+		return nil, fmt.Errorf("%w: object stream %d not found", errSyntax, num)
+	}
+	obj, err := r.objectAt(entry.offset)
+	if err != nil {
+		return nil, err
+	}
+	stream, ok := obj.(Stream)
+	if !ok {
+		return nil, fmt.Errorf("%w: object %d is not a stream", errSyntax, num)
+	}
+	data, err := r.decode(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	n, _ := stream.Dict["N"].(int)
+	first, _ := stream.Dict["First"].(int)
+	stm = &objStm{data: data, offsets: make(map[int]int, n)}
+	p := &parser{data: data}
+	for i := 0; i < n; i++ {
+		objNum, err1 := p.token()
+		offset, err2 := p.token()
+		if err1 != nil || err2 != nil {
+			break
+		}
+		o, ok1 := objNum.(int)
+		off, ok2 := offset.(int)
+		if ok1 && ok2 {
+			stm.offsets[o] = first + off
+		}
+	}
+	r.mu.Lock()
+	r.objStms[num] = stm
+	r.mu.Unlock()
+	return stm, nil
+}
+
+// imageFilters are image codecs, data encoded with them is left to image decoders
+var imageFilters = map[Name]bool{"DCTDecode": true, "JPXDecode": true, "CCITTFaxDecode": true, "JBIG2Decode": true}
+
+// decode applies stream filters, data must not be left encoded with an image codec
+func (r *Reader) decode(stm Stream) ([]byte, error) {
+	data, filter, err := r.Decode(stm)
+	if err == nil && filter != "" {
+		err = fmt.Errorf("unsupported pdf filter %s", filter)
+	}
+	return data, err
+}
+
+// Decode applies general stream filters: FlateDecode, ASCIIHexDecode, ASCII85Decode and
+// RunLengthDecode. Decoding stops at an image codec like DCTDecode, its data is returned
+// still encoded together with the name of the filter, the name is empty otherwise
+func (r *Reader) Decode(stm Stream) ([]byte, Name, error) {
+	filters := r.Resolve(stm.Dict["Filter"])
+	params := r.Resolve(stm.Dict["DecodeParms"])
+	if name, ok := filters.(Name); ok {
+		filters = Array{name}
+		params = Array{params}
+	}
+	filterList, _ := filters.(Array)
+	paramList, _ := params.(Array)
+
+	data := stm.Data
+	for i, f := range filterList {
+		var err error
+		switch filter, _ := r.Resolve(f).(Name); filter {
+		case "FlateDecode":
+			data, err = inflate(data)
+			if err != nil {
+				return nil, "", err
+			}
+			if i < len(paramList) {
+				if p, ok := r.Resolve(paramList[i]).(Dict); ok {
+					if data, err = unpredict(data, p); err != nil {
+						return nil, "", err
+					}
+				}
+			}
+		case "ASCIIHexDecode":
+			data, err = asciiHex(data)
+		case "ASCII85Decode":
+			data, err = ascii85Decode(data)
+		case "RunLengthDecode":
+			data = runLength(data)
+		default:
+			if imageFilters[filter] {
+				return data, filter, nil
+			}
+			return nil, "", fmt.Errorf("unsupported pdf filter %v", f)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return data, "", nil
+}
+
+// inflate decompresses zlib data
+func inflate(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	// truncated streams are common, decoded part is still usable
+	decoded, err := io.ReadAll(zr)
+	if err != nil && len(decoded) == 0 {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// asciiHex decodes hex digits up to > ignoring whitespace, odd number of digits is padded with 0
+func asciiHex(data []byte) ([]byte, error) {
+	if end := bytes.IndexByte(data, '>'); end >= 0 {
+		data = data[:end]
+	}
+	var digits []byte
+	for _, c := range data {
+		if !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	if _, err := hex.Decode(out, digits); err != nil {
+		return nil, fmt.Errorf("%w: bad hex data", errSyntax)
+	}
+	return out, nil
+}
+
+// ascii85Decode decodes data up to ~> ignoring whitespace
+func ascii85Decode(data []byte) ([]byte, error) {
+	if end := bytes.Index(data, []byte("~>")); end >= 0 {
+		data = data[:end]
+	}
+	data = bytes.TrimPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("<~"))
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad ascii85 data", errSyntax)
+	}
+	return out[:n], nil
+}
+
+// runLength decodes runs: length byte below 128 copies next length+1 bytes,
+// above 128 repeats next byte 257-length times and 128 ends data
+func runLength(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data) && data[i] != 128; {
+		n := int(data[i])
+		if n < 128 {
+			end := min(i+2+n, len(data))
+			out = append(out, data[i+1:end]...)
+			i = end
+			continue
+		}
+		if i+1 < len(data) {
+			out = append(out, bytes.Repeat(data[i+1:i+2], 257-n)...)
+		}
+		i += 2
+	}
+	return out
+}
+
+// unpredict reverses PNG predictors of Flate encoded data
+func unpredict(data []byte, params Dict) ([]byte, error) {
+	predictor, _ := params["Predictor"].(int)
+	if predictor < 10 {
+		return data, nil
+	}
+	columns, ok := params["Columns"].(int)
+	if !ok {
+		columns = 1
+	}
+	colors, ok := params["Colors"].(int)
+	if !ok {
+		colors = 1
+	}
+	bpc, ok := params["BitsPerComponent"].(int)
+	if !ok {
+		bpc = 8
+	}
+	bpp := max(colors*bpc/8, 1)
+	rowSize := (columns*colors*bpc + 7) / 8
+
+	out := make([]byte, 0, len(data))
+	prev := make([]byte, rowSize)
+	for pos := 0; pos+rowSize+1 <= len(data); pos += rowSize + 1 {
+		filter, row := data[pos], append([]byte(nil), data[pos+1:pos+1+rowSize]...)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 0:
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("%w: unknown png predictor %d", errSyntax, filter)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
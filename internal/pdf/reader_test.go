@@ -0,0 +1,262 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// buildPDF writes objects numbered from 1 with a classic xref table, catalog is object 1
+func buildPDF(trailer string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, xref)
+	return buf.Bytes()
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+// twoPages is a catalog with pages tree of objects 3 and 4, page 3 holds annotations
+func twoPages(catalog, annots string, extra ...string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R " + catalog + " >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 400 500] >>",
+		"<< /Type /Page /Parent 2 0 R /Annots [" + annots + "] >>",
+		"<< /Type /Page /Parent 2 0 R >>",
+	}
+	return buildPDF("", append(objects, extra...)...)
+}
+
+type linksTestCase struct {
+	comment     string
+	data        []byte
+	expectedVal []Link
+}
+
+var linkRect = Rect{10, 20, 110, 40}
+
+var LinksTestCase = []linksTestCase{
+	{
+		comment:     "URI action",
+		data:        twoPages("", "<< /Subtype /Link /Rect [10 20 110 40] /A << /S /URI /URI (https://example.com/) >> >>"),
+		expectedVal: []Link{{URI: "https://example.com/", Rect: linkRect}},
+	},
+	{
+		comment:     "GoTo action with page reference and flipped rect corners",
+		data:        twoPages("", "<< /Subtype /Link /Rect [110 40 10 20] /A << /S /GoTo /D [4 0 R /Fit] >> >>"),
+		expectedVal: []Link{{Page: 2, Rect: linkRect}},
+	},
+	{
+		comment:     "Dest array in annotation referenced indirectly",
+		data:        twoPages("", "5 0 R", "<< /Subtype /Link /Rect [10 20 110 40] /Dest [3 0 R /XYZ 0 0 0] >>"),
+		expectedVal: []Link{{Page: 1, Rect: linkRect}},
+	},
+	{
+		comment:     "Named destination in catalog Dests",
+		data:        twoPages("/Dests << /intro [4 0 R /Fit] >>", "<< /Subtype /Link /Rect [10 20 110 40] /Dest /intro >>"),
+		expectedVal: []Link{{Page: 2, Rect: linkRect}},
+	},
+	{
+		comment: "Named destination in name tree with D dictionary",
+		data: twoPages("/Names << /Dests 5 0 R >>", "<< /Subtype /Link /Rect [10 20 110 40] /A << /S /GoTo /D (sec.2) >> >>",
+			"<< /Kids [6 0 R] >>",
+			"<< /Limits [(sec.1) (sec.3)] /Names [(sec.1) [3 0 R /Fit] (sec.2) << /D [4 0 R /Fit] >>] >>"),
+		expectedVal: []Link{{Page: 2, Rect: linkRect}},
+	},
+	{
+		comment: "Other annotations, actions and unknown destinations are left out",
+		data: twoPages("", "<< /Subtype /Text /Rect [0 0 1 1] >> "+
+			"<< /Subtype /Link /Rect [0 0 1 1] /A << /S /Launch /F (app) >> >> "+
+			"<< /Subtype /Link /Rect [0 0 1 1] /Dest /missing >> "+
+			"<< /Subtype /Link /A << /S /URI /URI (https://no.rect/) >> >>"),
+		expectedVal: []Link{},
+	},
+}
+
+func TestLinks(t *testing.T) {
+	for _, tc := range LinksTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			r, err := NewReader(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.Links(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.expectedVal) {
+				t.Errorf("%s test. want: %+v, got: %+v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
+
+func TestPageBox(t *testing.T) {
+	data := buildPDF("",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 3 /MediaBox [0 0 400 500] /Rotate 90 >>",
+		"<< /Type /Page /Parent 2 0 R /CropBox [-10 50 300 600] /Rotate -90 >>",
+		"<< /Type /Pages /Parent 2 0 R /Kids [5 0 R] /Count 1 /CropBox [0 0 200 200] >>",
+		"<< /Type /Page /Parent 4 0 R /MediaBox [100 100 300 400] >>",
+	)
+	r, err := NewReader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.NumPage() != 2 {
+		t.Fatalf("page box test. want: 2 pages, got: %d", r.NumPage())
+	}
+
+	expected := []struct {
+		box    Rect
+		rotate int
+	}{
+		{Rect{0, 50, 300, 500}, 270},
+		{Rect{100, 100, 200, 200}, 90},
+	}
+	for i, want := range expected {
+		box, rotate, err := r.PageBox(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if box != want.box || rotate != want.rotate {
+			t.Errorf("page box test. page %d want: %v %d, got: %v %d", i+1, want.box, want.rotate, box, rotate)
+		}
+	}
+	if _, _, err = r.PageBox(2); err == nil {
+		t.Error("page box test. want error for page out of range")
+	}
+}
+
+func TestXrefStream(t *testing.T) {
+	// objects 2-5 are packed into object stream 6, xref stream 7 uses PNG Up predictor
+	objects := []string{
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 400 500] >>",
+		"<< /Type /Page /Parent 2 0 R /Annots [4 0 R] >>",
+		"<< /Subtype /Link /Rect [10 20 110 40] /A 5 0 R >>",
+		"<< /S /URI /URI (https://example.com/) >>",
+	}
+	var header, body bytes.Buffer
+	for i, obj := range objects {
+		fmt.Fprintf(&header, "%d %d ", i+2, body.Len())
+		body.WriteString(obj + " ")
+	}
+	stm := deflate(append(header.Bytes(), body.Bytes()...))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	catalog := buf.Len()
+	buf.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	objStm := buf.Len()
+	fmt.Fprintf(&buf, "6 0 obj\n<< /Type /ObjStm /N 4 /First %d /Length %d /Filter /FlateDecode >>\nstream\n", header.Len(), len(stm))
+	buf.Write(stm)
+	buf.WriteString("\nendstream\nendobj\n")
+	xref := buf.Len()
+
+	// entries are type, 2 bytes of offset or stream number, 1 byte of generation or index
+	entries := [][4]byte{{0, 0, 0, 255}, {1, byte(catalog >> 8), byte(catalog), 0}}
+	for i := range objects {
+		entries = append(entries, [4]byte{2, 0, 6, byte(i)})
+	}
+	entries = append(entries, [4]byte{1, byte(objStm >> 8), byte(objStm), 0}, [4]byte{1, byte(xref >> 8), byte(xref), 0})
+	var rows []byte
+	prev := [4]byte{}
+	for _, e := range entries {
+		rows = append(rows, 2)
+		for i := range e {
+			rows = append(rows, e[i]-prev[i])
+		}
+		prev = e
+	}
+	xrefData := deflate(rows)
+	fmt.Fprintf(&buf, "7 0 obj\n<< /Type /XRef /Size 8 /Root 1 0 R /W [1 2 1] /Length %d /Filter /FlateDecode "+
+		"/DecodeParms << /Predictor 12 /Columns 4 >> >>\nstream\n", len(xrefData))
+	buf.Write(xrefData)
+	fmt.Fprintf(&buf, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", xref)
+
+	r, err := NewReader(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	links, err := r.Links(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Link{{URI: "https://example.com/", Rect: linkRect}}
+	if fmt.Sprint(links) != fmt.Sprint(want) {
+		t.Errorf("xref stream test. want: %+v, got: %+v", want, links)
+	}
+}
+
+func TestRepair(t *testing.T) {
+	data := twoPages("", "<< /Subtype /Link /Rect [10 20 110 40] /A << /S /URI /URI (https://example.com/) >> >>")
+	// shift all objects so that xref offsets point to wrong places
+	data = append([]byte("%PDF-1.4\n% padding to break offsets\n"), data[len("%PDF-1.4\n"):]...)
+	r, err := NewReader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links, err := r.Links(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0].URI != "https://example.com/" {
+		t.Errorf("repair test. want link to https://example.com/, got: %+v", links)
+	}
+}
+
+func TestEncrypted(t *testing.T) {
+	data := buildPDF("/Encrypt << /Filter /Standard >>", "<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>", "<< /Type /Page /Parent 2 0 R >>")
+	if _, err := NewReader(data); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("encrypted test. want: %v, got: %v", ErrEncrypted, err)
+	}
+}
+
+type parserTestCase struct {
+	comment     string
+	inputValue  string
+	expectedVal Object
+}
+
+var ParserTestCase = []parserTestCase{
+	{comment: "Literal string with escapes", inputValue: `(a\(b\)\n\101\
+c)`, expectedVal: String("a(b)\nAc")},
+	{comment: "Hex string with odd digits", inputValue: "<48 6 9 7>", expectedVal: String("Hip")},
+	{comment: "Name with hex escape", inputValue: "/A#20B", expectedVal: Name("A B")},
+	{comment: "Array of numbers and reference", inputValue: "[1 2.5 -3 4 0 R]", expectedVal: Array{1, 2.5, -3, Ref{4, 0}}},
+	{comment: "Dictionary with comment", inputValue: "<< /K true % note\n/N null >>", expectedVal: Dict{"K": true, "N": nil}},
+}
+
+func TestParser(t *testing.T) {
+	for _, tc := range ParserTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			p := &parser{data: []byte(tc.inputValue)}
+			got, err := p.object()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprintf("%#v", got) != fmt.Sprintf("%#v", tc.expectedVal) {
+				t.Errorf("%s test. want: %#v, got: %#v", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package sheet
+
+import (
+	"image"
+	"image/color"
+	"sort"
+	"strconv"
+	"sync"
+
+	"golang.org/x/image/draw"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// ContactSheet collects downscaled pages and lays them out in a grid
+type ContactSheet struct {
+	Columns    int
+	Rows       int
+	CellX      int
+	CellY      int
+	Spacing    int
+	Background color.Color
+	Captions   bool
+
+	mu    sync.Mutex
+	cells map[int]*image.RGBA
+}
+
+// Collect fits page image into a grid cell and stores it until sheets are assembled
+func (cs *ContactSheet) Collect(pageNum int, img *image.RGBA) error {
+	cell := imageutils.Fit(img, cs.CellX, cs.CellY)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.cells == nil {
+		cs.cells = make(map[int]*image.RGBA)
+	}
+	cs.cells[pageNum] = cell
+	return nil
+}
+
+// Sheets lays collected pages out in page order, starting a new sheet every Rows rows
+func (cs *ContactSheet) Sheets() ([]*image.RGBA, error) {
+	pageNums := make([]int, 0, len(cs.cells))
+	for pageNum := range cs.cells {
+		pageNums = append(pageNums, pageNum)
+	}
+	sort.Ints(pageNums)
+
+	perSheet := cs.Columns * cs.Rows
+	captionSize := cs.captionSize()
+	captionHeight := 0
+	if cs.Captions {
+		captionHeight = int(captionSize * 1.5)
+	}
+	rowHeight := cs.CellY + captionHeight + cs.Spacing
+	width := cs.Columns*(cs.CellX+cs.Spacing) + cs.Spacing
+	textColor := contrastColor(cs.Background)
+
+	var sheets []*image.RGBA
+	for start := 0; start < len(pageNums); start += perSheet {
+		chunk := pageNums[start:min(start+perSheet, len(pageNums))]
+		rows := (len(chunk) + cs.Columns - 1) / cs.Columns
+
+		sheetImg := image.NewRGBA(image.Rect(0, 0, width, rows*rowHeight+cs.Spacing))
+		draw.Draw(sheetImg, sheetImg.Bounds(), image.NewUniform(cs.Background), image.Point{}, draw.Src)
+
+		for i, pageNum := range chunk {
+			cellX := cs.Spacing + (i%cs.Columns)*(cs.CellX+cs.Spacing)
+			cellY := cs.Spacing + (i/cs.Columns)*rowHeight
+
+			cell := cs.cells[pageNum]
+			offset := image.Pt(cellX+(cs.CellX-cell.Bounds().Dx())/2, cellY+(cs.CellY-cell.Bounds().Dy())/2)
+			draw.Draw(sheetImg, cell.Bounds().Add(offset), cell, image.Point{}, draw.Over)
+
+			if cs.Captions {
+				// pages are stored zero-based like in the document
+				caption, err := imageutils.TextImage(strconv.Itoa(pageNum+1), captionSize, textColor)
+				if err != nil {
+					return nil, err
+				}
+				captionPos := image.Pt(cellX+(cs.CellX-caption.Bounds().Dx())/2,
+					cellY+cs.CellY+(captionHeight-caption.Bounds().Dy())/2)
+				draw.Draw(sheetImg, caption.Bounds().Add(captionPos), caption, image.Point{}, draw.Over)
+			}
+		}
+		sheets = append(sheets, sheetImg)
+	}
+
+	return sheets, nil
+}
+
+// captionSize scales caption font with cell height
+func (cs *ContactSheet) captionSize() float64 {
+	return max(10, float64(cs.CellY)/16)
+}
+
+// contrastColor picks black or white text depending on background brightness
+func contrastColor(bg color.Color) color.Color {
+	r, g, b, _ := bg.RGBA()
+	luma := (299*r + 587*g + 114*b) / 1000
+	if luma > 0x7fff {
+		return color.Black
+	}
+	return color.White
+}
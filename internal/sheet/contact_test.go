@@ -0,0 +1,105 @@
+package sheet
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+// pageImage returns page filled with a color identifying its number
+func pageImage(pageNum, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(pageColor(pageNum)), image.Point{}, draw.Src)
+	return img
+}
+
+func pageColor(pageNum int) color.RGBA {
+	return color.RGBA{R: uint8(10 * (pageNum + 1)), A: 255}
+}
+
+type sheetTestCase struct {
+	comment string
+	sheet   int
+	point   image.Point
+	// page is expected at the point, -1 for background
+	page int
+}
+
+// 8 pages in sheets of 3 columns and 2 rows with 40x30 cells and 5 pixels spacing,
+// second sheet holds a single partial row
+var ContactSheetTestCase = []sheetTestCase{
+	{
+		comment: "First cell of first sheet",
+		sheet:   0,
+		point:   image.Pt(25, 20),
+		page:    0,
+	},
+	{
+		comment: "Last cell of first sheet",
+		sheet:   0,
+		point:   image.Pt(115, 55),
+		page:    5,
+	},
+	{
+		comment: "Spacing between cells",
+		sheet:   0,
+		point:   image.Pt(47, 20),
+		page:    -1,
+	},
+	{
+		comment: "First cell of partial row",
+		sheet:   1,
+		point:   image.Pt(25, 20),
+		page:    6,
+	},
+	{
+		comment: "Last page of partial row",
+		sheet:   1,
+		point:   image.Pt(70, 20),
+		page:    7,
+	},
+	{
+		comment: "Empty cell of partial row",
+		sheet:   1,
+		point:   image.Pt(115, 20),
+		page:    -1,
+	},
+}
+
+func TestContactSheet(t *testing.T) {
+	background := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	cs := &ContactSheet{Columns: 3, Rows: 2, CellX: 40, CellY: 30, Spacing: 5, Background: background}
+	for pageNum := 7; pageNum >= 0; pageNum-- {
+		if err := cs.Collect(pageNum, pageImage(pageNum, 80, 60)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sheets, err := cs.Sheets()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := []image.Point{image.Pt(140, 75), image.Pt(140, 40)}
+	if len(sheets) != len(sizes) {
+		t.Fatalf("sheets test. want: %d sheets, got: %d", len(sizes), len(sheets))
+	}
+	for i, size := range sizes {
+		if got := sheets[i].Bounds().Size(); got != size {
+			t.Errorf("sheet %d size test. want: %v, got: %v", i+1, size, got)
+		}
+	}
+
+	for _, tc := range ContactSheetTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			want := background
+			if tc.page >= 0 {
+				want = pageColor(tc.page)
+			}
+			if got := sheets[tc.sheet].RGBAAt(tc.point.X, tc.point.Y); got != want {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, want, got)
+			}
+		})
+	}
+}
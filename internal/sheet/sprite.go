@@ -0,0 +1,188 @@
+package sheet
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// Sprite collects thumbnails and packs them tightly into sprite sheets
+type Sprite struct {
+	Columns int
+	// Rows limits rows per sheet, 0 means as many as fit into MaxY
+	Rows int
+	// MaxX and MaxY limit sheet size in pixels, 0 means no limit
+	MaxX       int
+	MaxY       int
+	Background color.Color
+
+	mu     sync.Mutex
+	thumbs map[int]*image.RGBA
+}
+
+// SpriteSheet is an assembled sprite image with rectangles of the pages on it
+type SpriteSheet struct {
+	Image *image.RGBA
+	Tiles []Tile
+}
+
+// Tile is a rectangle of a page on a sprite sheet, pages are zero-based
+type Tile struct {
+	Page int
+	Rect image.Rectangle
+}
+
+// Collect stores thumbnail until sprite sheets are assembled
+func (s *Sprite) Collect(pageNum int, img *image.RGBA) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.thumbs == nil {
+		s.thumbs = make(map[int]*image.RGBA)
+	}
+	s.thumbs[pageNum] = img
+	return nil
+}
+
+// Sheets packs collected thumbnails in page order into a grid of cells sized to the largest thumbnail
+func (s *Sprite) Sheets() []SpriteSheet {
+	pageNums := make([]int, 0, len(s.thumbs))
+	cellX, cellY := 1, 1
+	for pageNum, thumb := range s.thumbs {
+		pageNums = append(pageNums, pageNum)
+		cellX = max(cellX, thumb.Bounds().Dx())
+		cellY = max(cellY, thumb.Bounds().Dy())
+	}
+	sort.Ints(pageNums)
+
+	columns := s.Columns
+	if s.MaxX > 0 {
+		columns = min(columns, max(s.MaxX/cellX, 1))
+	}
+	rows := s.Rows
+	if s.MaxY > 0 {
+		fitRows := max(s.MaxY/cellY, 1)
+		if rows == 0 || rows > fitRows {
+			rows = fitRows
+		}
+	}
+	if rows == 0 {
+		rows = (len(pageNums) + columns - 1) / columns
+	}
+	perSheet := columns * rows
+
+	var sheets []SpriteSheet
+	for start := 0; start < len(pageNums); start += perSheet {
+		chunk := pageNums[start:min(start+perSheet, len(pageNums))]
+		sheetRows := (len(chunk) + columns - 1) / columns
+		sheetColumns := min(len(chunk), columns)
+
+		sheet := SpriteSheet{Image: image.NewRGBA(image.Rect(0, 0, sheetColumns*cellX, sheetRows*cellY))}
+		draw.Draw(sheet.Image, sheet.Image.Bounds(), image.NewUniform(s.Background), image.Point{}, draw.Src)
+
+		for i, pageNum := range chunk {
+			thumb := s.thumbs[pageNum]
+			rect := thumb.Bounds().Sub(thumb.Bounds().Min).Add(image.Pt((i%columns)*cellX, (i/columns)*cellY))
+			draw.Draw(sheet.Image, rect, thumb, thumb.Bounds().Min, draw.Over)
+			sheet.Tiles = append(sheet.Tiles, Tile{Page: pageNum, Rect: rect})
+		}
+		sheets = append(sheets, sheet)
+	}
+
+	return sheets
+}
+
+// spriteMap is JSON map of sprite sheets
+type spriteMap struct {
+	Sheets []spriteMapSheet `json:"sheets"`
+	Pages  []spriteMapPage  `json:"pages"`
+}
+
+type spriteMapSheet struct {
+	File   string `json:"file"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type spriteMapPage struct {
+	Page  int    `json:"page"`
+	Sheet string `json:"sheet"`
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	W     int    `json:"w"`
+	H     int    `json:"h"`
+}
+
+// WriteSpriteMap writes rectangles of pages on sprite sheets saved as files in json, css or vtt format.
+// In WebVTT every page is shown for interval starting from the first page
+func WriteSpriteMap(w io.Writer, format string, files []string, sheets []SpriteSheet, interval time.Duration) error {
+	switch format {
+	case "json":
+		var m spriteMap
+		for i, sheet := range sheets {
+			m.Sheets = append(m.Sheets, spriteMapSheet{
+				File:   files[i],
+				Width:  sheet.Image.Bounds().Dx(),
+				Height: sheet.Image.Bounds().Dy(),
+			})
+			for _, tile := range sheet.Tiles {
+				m.Pages = append(m.Pages, spriteMapPage{
+					Page:  tile.Page + 1,
+					Sheet: files[i],
+					X:     tile.Rect.Min.X,
+					Y:     tile.Rect.Min.Y,
+					W:     tile.Rect.Dx(),
+					H:     tile.Rect.Dy(),
+				})
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	case "css":
+		if _, err := fmt.Fprint(w, ".sprite {\n  display: inline-block;\n  background-repeat: no-repeat;\n}\n"); err != nil {
+			return err
+		}
+		for i, sheet := range sheets {
+			for _, tile := range sheet.Tiles {
+				_, err := fmt.Fprintf(w, "\n.sprite-%d {\n  background-image: url(%q);\n"+
+					"  background-position: %dpx %dpx;\n  width: %dpx;\n  height: %dpx;\n}\n",
+					tile.Page+1, files[i], -tile.Rect.Min.X, -tile.Rect.Min.Y, tile.Rect.Dx(), tile.Rect.Dy())
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case "vtt":
+		if _, err := fmt.Fprint(w, "WEBVTT\n"); err != nil {
+			return err
+		}
+		var start time.Duration
+		for i, sheet := range sheets {
+			for _, tile := range sheet.Tiles {
+				_, err := fmt.Fprintf(w, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+					vttTimestamp(start), vttTimestamp(start+interval), files[i],
+					tile.Rect.Min.X, tile.Rect.Min.Y, tile.Rect.Dx(), tile.Rect.Dy())
+				if err != nil {
+					return err
+				}
+				start += interval
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported sprite map format: %s", format)
+}
+
+// vttTimestamp formats duration as WebVTT hh:mm:ss.ttt timestamp
+func vttTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,123 @@
+package sheet
+
+import (
+	"image"
+	"testing"
+	"time"
+)
+
+type spriteTestCase struct {
+	comment    string
+	columns    int
+	rows       int
+	maxX       int
+	maxY       int
+	sizes      []image.Point
+	tiles      [][]Tile
+	sheetSizes []image.Point
+}
+
+var SpriteTestCase = []spriteTestCase{
+	{
+		comment: "Rows limited by sheet height, partial last sheet",
+		columns: 3,
+		maxY:    25,
+		sizes:   []image.Point{{20, 10}, {20, 10}, {16, 10}, {20, 10}, {20, 10}, {20, 10}, {20, 10}},
+		tiles: [][]Tile{
+			{
+				{Page: 0, Rect: image.Rect(0, 0, 20, 10)},
+				{Page: 1, Rect: image.Rect(20, 0, 40, 10)},
+				{Page: 2, Rect: image.Rect(40, 0, 56, 10)},
+				{Page: 3, Rect: image.Rect(0, 10, 20, 20)},
+				{Page: 4, Rect: image.Rect(20, 10, 40, 20)},
+				{Page: 5, Rect: image.Rect(40, 10, 60, 20)},
+			},
+			{
+				{Page: 6, Rect: image.Rect(0, 0, 20, 10)},
+			},
+		},
+		sheetSizes: []image.Point{{60, 20}, {20, 10}},
+	},
+	{
+		comment: "Columns limited by sheet width, partial last row",
+		columns: 10,
+		maxX:    45,
+		sizes:   []image.Point{{20, 10}, {20, 10}, {20, 10}},
+		tiles: [][]Tile{
+			{
+				{Page: 0, Rect: image.Rect(0, 0, 20, 10)},
+				{Page: 1, Rect: image.Rect(20, 0, 40, 10)},
+				{Page: 2, Rect: image.Rect(0, 10, 20, 20)},
+			},
+		},
+		sheetSizes: []image.Point{{40, 20}},
+	},
+}
+
+func TestSpriteSheets(t *testing.T) {
+	for _, tc := range SpriteTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			s := &Sprite{Columns: tc.columns, Rows: tc.rows, MaxX: tc.maxX, MaxY: tc.maxY, Background: pageColor(100)}
+			for pageNum, size := range tc.sizes {
+				if err := s.Collect(pageNum, pageImage(pageNum, size.X, size.Y)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sheets := s.Sheets()
+			if len(sheets) != len(tc.tiles) {
+				t.Fatalf("%s test. want: %d sheets, got: %d", tc.comment, len(tc.tiles), len(sheets))
+			}
+			for i, sheet := range sheets {
+				if got := sheet.Image.Bounds().Size(); got != tc.sheetSizes[i] {
+					t.Errorf("%s test. sheet %d want size: %v, got: %v", tc.comment, i+1, tc.sheetSizes[i], got)
+				}
+				if len(sheet.Tiles) != len(tc.tiles[i]) {
+					t.Fatalf("%s test. sheet %d want: %v, got: %v", tc.comment, i+1, tc.tiles[i], sheet.Tiles)
+				}
+				for j, tile := range sheet.Tiles {
+					if tile != tc.tiles[i][j] {
+						t.Errorf("%s test. sheet %d tile %d want: %v, got: %v", tc.comment, i+1, j, tc.tiles[i][j], tile)
+					}
+					if got := sheet.Image.RGBAAt(tile.Rect.Min.X, tile.Rect.Min.Y); got != pageColor(tile.Page) {
+						t.Errorf("%s test. page %d want color: %v, got: %v", tc.comment, tile.Page+1, pageColor(tile.Page), got)
+					}
+				}
+			}
+		})
+	}
+}
+
+type vttTimestampTestCase struct {
+	comment     string
+	inputValue  time.Duration
+	expectedVal string
+}
+
+var VTTTimestampTestCase = []vttTimestampTestCase{
+	{
+		comment:     "Zero",
+		inputValue:  0,
+		expectedVal: "00:00:00.000",
+	},
+	{
+		comment:     "Fraction of second",
+		inputValue:  1500 * time.Millisecond,
+		expectedVal: "00:00:01.500",
+	},
+	{
+		comment:     "Over an hour",
+		inputValue:  time.Hour + 2*time.Minute + 3*time.Second,
+		expectedVal: "01:02:03.000",
+	},
+}
+
+func TestVTTTimestamp(t *testing.T) {
+	for _, tc := range VTTTimestampTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			if got := vttTimestamp(tc.inputValue); got != tc.expectedVal {
+				t.Errorf("%s test. want: %s, got: %s", tc.comment, tc.expectedVal, got)
+			}
+		})
+	}
+}
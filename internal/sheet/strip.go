@@ -0,0 +1,213 @@
+package sheet
+
+import (
+	"compress/flate"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"golang.org/x/image/draw"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// Strip collects pages and concatenates them into one long image. Collected pages are kept
+// in temporary files, so only the part being assembled is held in memory
+type Strip struct {
+	Vertical bool
+	// Gap between pages filled with Separator color
+	Gap       int
+	Separator color.Color
+	// MaxLength limits strip length in pixels, longer strips are split into parts between pages
+	MaxLength int
+	// Dir holds temporary files of collected pages, system temporary directory when empty
+	Dir string
+
+	mu    sync.Mutex
+	pages map[int]stripPage
+}
+
+// stripPage is a collected page stored as compressed pixels in a temporary file
+type stripPage struct {
+	path string
+	size image.Point
+}
+
+// Collect stores page in a temporary file until strip is assembled
+func (s *Strip) Collect(pageNum int, img *image.RGBA) error {
+	f, err := os.CreateTemp(s.Dir, "strip-*.raw")
+	if err != nil {
+		return err
+	}
+	if err = writePixels(f, img); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pages == nil {
+		s.pages = make(map[int]stripPage)
+	}
+	if old, ok := s.pages[pageNum]; ok {
+		os.Remove(old.path)
+	}
+	s.pages[pageNum] = stripPage{path: f.Name(), size: img.Bounds().Size()}
+	return nil
+}
+
+// Save concatenates pages in page order and passes parts of the strip to save one by one
+// with zero-based part number and number of parts. Pages are resized to the smallest width
+// (height for horizontal strip) so none of them is upscaled. Temporary files are removed
+func (s *Strip) Save(save func(part, parts int, img *image.RGBA) error) error {
+	defer s.remove()
+
+	pageNums := make([]int, 0, len(s.pages))
+	cross := 0
+	for pageNum, p := range s.pages {
+		pageNums = append(pageNums, pageNum)
+		_, c := s.size(p.size)
+		if cross == 0 || c < cross {
+			cross = c
+		}
+	}
+	sort.Ints(pageNums)
+
+	// pages are split into parts by their sizes before any of them is loaded
+	var parts [][]int
+	var lengths []int
+	for start := 0; start < len(pageNums); {
+		length, _ := s.normalizedSize(s.pages[pageNums[start]].size, cross)
+		end := start + 1
+		for end < len(pageNums) {
+			next, _ := s.normalizedSize(s.pages[pageNums[end]].size, cross)
+			if length+s.Gap+next > s.MaxLength {
+				break
+			}
+			length += s.Gap + next
+			end++
+		}
+		parts = append(parts, pageNums[start:end])
+		lengths = append(lengths, length)
+		start = end
+	}
+
+	for i, part := range parts {
+		img, err := s.join(part, lengths[i], cross)
+		if err != nil {
+			return err
+		}
+		if err = save(i, len(parts), img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove deletes temporary files of collected pages
+func (s *Strip) remove() {
+	for _, p := range s.pages {
+		os.Remove(p.path)
+	}
+	s.pages = nil
+}
+
+// normalizedSize returns page length along the strip and its cross size once it's resized
+// to common cross size, pages longer than MaxLength are shrunk to fit keeping aspect ratio
+func (s *Strip) normalizedSize(size image.Point, cross int) (int, int) {
+	length, c := s.size(size)
+	if c != cross {
+		length = max(int(float64(length)*float64(cross)/float64(c)+0.5), 1)
+	}
+	if length <= s.MaxLength {
+		return length, cross
+	}
+	return s.MaxLength, max(int(float64(cross)*float64(s.MaxLength)/float64(length)), 1)
+}
+
+// normalize resizes page to its normalized size
+func (s *Strip) normalize(img *image.RGBA, cross int) *image.RGBA {
+	length, c := s.normalizedSize(img.Bounds().Size(), cross)
+	if l0, c0 := s.size(img.Bounds().Size()); l0 == length && c0 == c {
+		return img
+	}
+	if s.Vertical {
+		return imageutils.Resize(img, c, length)
+	}
+	return imageutils.Resize(img, length, c)
+}
+
+// join loads pages and draws them one after another separated by gaps,
+// pages narrower than cross size are centered
+func (s *Strip) join(pageNums []int, length, cross int) (*image.RGBA, error) {
+	rect := image.Rect(0, 0, length, cross)
+	if s.Vertical {
+		rect = image.Rect(0, 0, cross, length)
+	}
+	dstImg := image.NewRGBA(rect)
+	draw.Draw(dstImg, rect, image.NewUniform(s.Separator), image.Point{}, draw.Src)
+
+	offset := 0
+	for _, pageNum := range pageNums {
+		img, err := readPixels(s.pages[pageNum])
+		if err != nil {
+			return nil, err
+		}
+		img = s.normalize(img, cross)
+		l, c := s.size(img.Bounds().Size())
+		pos := image.Pt(offset, (cross-c)/2)
+		if s.Vertical {
+			pos = image.Pt((cross-c)/2, offset)
+		}
+		draw.Draw(dstImg, img.Bounds().Sub(img.Bounds().Min).Add(pos), img, img.Bounds().Min, draw.Over)
+		offset += l + s.Gap
+	}
+	return dstImg, nil
+}
+
+// size returns length along the strip and cross size of an image of a given size
+func (s *Strip) size(size image.Point) (int, int) {
+	if s.Vertical {
+		return size.Y, size.X
+	}
+	return size.X, size.Y
+}
+
+// writePixels writes compressed pixels of the image row by row, pixels are kept as is
+// unlike in png which stores colors without premultiplied alpha
+func writePixels(w io.Writer, img *image.RGBA) error {
+	fw, err := flate.NewWriter(w, flate.BestSpeed)
+	if err != nil {
+		return err
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		i := img.PixOffset(b.Min.X, y)
+		if _, err = fw.Write(img.Pix[i : i+4*b.Dx()]); err != nil {
+			return err
+		}
+	}
+	return fw.Close()
+}
+
+// readPixels loads page written by writePixels
+func readPixels(p stripPage) (*image.RGBA, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img := image.NewRGBA(image.Rectangle{Max: p.size})
+	if _, err = io.ReadFull(flate.NewReader(f), img.Pix); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
@@ -0,0 +1,114 @@
+package sheet
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"testing"
+)
+
+type stripTestCase struct {
+	comment   string
+	vertical  bool
+	maxLength int
+	sizes     []image.Point
+	parts     []image.Point
+	// pages maps points of the first part to pages drawn there, -1 for separator
+	pages map[image.Point]int
+}
+
+var StripTestCase = []stripTestCase{
+	{
+		comment:   "Vertical strip in one part",
+		vertical:  true,
+		maxLength: 1000,
+		sizes:     []image.Point{{40, 30}, {80, 60}, {40, 30}},
+		parts:     []image.Point{{40, 100}},
+		pages: map[image.Point]int{
+			{20, 15}: 0,
+			{20, 32}: -1,
+			{20, 50}: 1,
+			{20, 85}: 2,
+		},
+	},
+	{
+		comment:   "Vertical strip split between pages",
+		vertical:  true,
+		maxLength: 70,
+		sizes:     []image.Point{{40, 30}, {40, 30}, {40, 30}},
+		parts:     []image.Point{{40, 65}, {40, 30}},
+		pages: map[image.Point]int{
+			{20, 15}: 0,
+			{20, 50}: 1,
+		},
+	},
+	{
+		comment:   "Horizontal strip resized to the lowest page",
+		maxLength: 1000,
+		sizes:     []image.Point{{40, 30}, {20, 15}},
+		parts:     []image.Point{{20 + 5 + 20, 15}},
+		pages: map[image.Point]int{
+			{10, 7}: 0,
+			{35, 7}: 1,
+		},
+	},
+	{
+		comment:   "Page longer than strip limit is shrunk and centered",
+		vertical:  true,
+		maxLength: 50,
+		sizes:     []image.Point{{40, 100}},
+		parts:     []image.Point{{40, 50}},
+		pages: map[image.Point]int{
+			{5, 25}:  -1,
+			{20, 25}: 0,
+		},
+	},
+}
+
+func TestStripSave(t *testing.T) {
+	separator := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	for _, tc := range StripTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			s := &Strip{Vertical: tc.vertical, Gap: 5, Separator: separator, MaxLength: tc.maxLength, Dir: t.TempDir()}
+			// pages are collected out of order like by concurrent workers
+			for pageNum := len(tc.sizes) - 1; pageNum >= 0; pageNum-- {
+				size := tc.sizes[pageNum]
+				if err := s.Collect(pageNum, pageImage(pageNum, size.X, size.Y)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var parts []*image.RGBA
+			err := s.Save(func(part, total int, img *image.RGBA) error {
+				if part != len(parts) || total != len(tc.parts) {
+					t.Errorf("%s test. want part %d of %d, got: %d of %d", tc.comment, len(parts), len(tc.parts), part, total)
+				}
+				parts = append(parts, img)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if files, _ := os.ReadDir(s.Dir); len(files) > 0 {
+				t.Errorf("%s test. want temporary files removed, got: %d", tc.comment, len(files))
+			}
+			if len(parts) != len(tc.parts) {
+				t.Fatalf("%s test. want: %d parts, got: %d", tc.comment, len(tc.parts), len(parts))
+			}
+			for i, part := range parts {
+				if got := part.Bounds().Size(); got != tc.parts[i] {
+					t.Errorf("%s test. part %d want size: %v, got: %v", tc.comment, i+1, tc.parts[i], got)
+				}
+			}
+			for point, pageNum := range tc.pages {
+				want := separator
+				if pageNum >= 0 {
+					want = pageColor(pageNum)
+				}
+				if got := parts[0].RGBAAt(point.X, point.Y); got != want {
+					t.Errorf("%s test. at %v want: %v, got: %v", tc.comment, point, want, got)
+				}
+			}
+		})
+	}
+}
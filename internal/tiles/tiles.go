@@ -0,0 +1,244 @@
+package tiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+
+	"github.com/dmikhr/pdfjuicer/internal/imageutils"
+)
+
+// supported tile pyramid layouts
+const (
+	FormatDZI  = "dzi"
+	FormatIIIF = "iiif"
+	FormatXYZ  = "xyz"
+)
+
+// Pyramid cuts rendered pages into multi-resolution tile pyramids
+type Pyramid struct {
+	Format   string
+	TileSize int
+	// Overlap is the number of extra pixels shared by neighbouring DZI tiles
+	Overlap  int
+	ImgType  string
+	SavePath string
+	Prefix   string
+	Postfix  string
+	// BaseURL is prepended to page directory in IIIF info.json identifiers
+	BaseURL string
+}
+
+// Collect writes tile pyramid with its descriptor for a page right away, so pages are
+// tiled in parallel by the workers that rendered them
+func (p *Pyramid) Collect(pageNum int, img *image.RGBA) error {
+	name := fmt.Sprintf("%s%03d%s", p.Prefix, pageNum+1, p.Postfix)
+	levels := halvings(img)
+
+	switch p.Format {
+	case FormatDZI:
+		return p.writeDZI(name, levels)
+	case FormatIIIF:
+		return p.writeIIIF(name, levels)
+	case FormatXYZ:
+		return p.writeXYZ(name, levels)
+	}
+	return fmt.Errorf("unsupported tiles format: %s", p.Format)
+}
+
+// writeDZI saves Deep Zoom pyramid: name.dzi descriptor and name_files/<level>/<col>_<row> tiles
+func (p *Pyramid) writeDZI(name string, levels []*image.RGBA) error {
+	full := levels[0].Bounds()
+	maxLevel := len(levels) - 1
+	ext := p.ext()
+
+	for i, levelImg := range levels {
+		levelDir := filepath.Join(p.SavePath, name+"_files", fmt.Sprint(maxLevel-i))
+		if err := os.MkdirAll(levelDir, 0755); err != nil {
+			return err
+		}
+		b := levelImg.Bounds()
+		for col := 0; col*p.TileSize < b.Dx(); col++ {
+			for row := 0; row*p.TileSize < b.Dy(); row++ {
+				rect := image.Rect(col*p.TileSize-p.Overlap, row*p.TileSize-p.Overlap,
+					(col+1)*p.TileSize+p.Overlap, (row+1)*p.TileSize+p.Overlap).Intersect(b)
+				tilePath := filepath.Join(levelDir, fmt.Sprintf("%d_%d.%s", col, row, ext))
+				if err := imageutils.Save(tilePath, p.ImgType, levelImg.SubImage(rect)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	descriptor := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Image xmlns="http://schemas.microsoft.com/deepzoom/2008" Format="%s" Overlap="%d" TileSize="%d">
+  <Size Width="%d" Height="%d"/>
+</Image>
+`, ext, p.Overlap, p.TileSize, full.Dx(), full.Dy())
+
+	return os.WriteFile(filepath.Join(p.SavePath, name+".dzi"), []byte(descriptor), 0644)
+}
+
+// iiifInfo is IIIF Image API 2.1 level 0 info.json
+type iiifInfo struct {
+	Context  string     `json:"@context"`
+	ID       string     `json:"@id"`
+	Protocol string     `json:"protocol"`
+	Width    int        `json:"width"`
+	Height   int        `json:"height"`
+	Profile  []string   `json:"profile"`
+	Tiles    []iiifTile `json:"tiles"`
+	Sizes    []iiifSize `json:"sizes"`
+}
+
+type iiifTile struct {
+	Width        int   `json:"width"`
+	ScaleFactors []int `json:"scaleFactors"`
+}
+
+type iiifSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// writeIIIF saves static IIIF tiles as name/<region>/<size>/0/default.<ext> with name/info.json
+func (p *Pyramid) writeIIIF(name string, levels []*image.RGBA) error {
+	full := levels[0].Bounds()
+	pageDir := filepath.Join(p.SavePath, name)
+	ext := p.ext()
+
+	info := iiifInfo{
+		Context:  "http://iiif.io/api/image/2/context.json",
+		ID:       strings.TrimSuffix(p.BaseURL, "/") + "/" + name,
+		Protocol: "http://iiif.io/api/image",
+		Width:    full.Dx(),
+		Height:   full.Dy(),
+		Profile:  []string{"http://iiif.io/api/image/2/level0.json"},
+	}
+	if p.BaseURL == "" {
+		info.ID = name
+	}
+
+	var scaleFactors []int
+	for i, levelImg := range levels {
+		scale := 1 << i
+		b := levelImg.Bounds()
+		scaleFactors = append(scaleFactors, scale)
+
+		for col := 0; col*p.TileSize < b.Dx(); col++ {
+			for row := 0; row*p.TileSize < b.Dy(); row++ {
+				rect := image.Rect(col*p.TileSize, row*p.TileSize,
+					(col+1)*p.TileSize, (row+1)*p.TileSize).Intersect(b)
+				// region is addressed in full resolution coordinates
+				region := image.Rect(rect.Min.X*scale, rect.Min.Y*scale,
+					min(rect.Max.X*scale, full.Dx()), min(rect.Max.Y*scale, full.Dy()))
+				tileDir := filepath.Join(pageDir,
+					fmt.Sprintf("%d,%d,%d,%d", region.Min.X, region.Min.Y, region.Dx(), region.Dy()),
+					fmt.Sprintf("%d,", rect.Dx()), "0")
+				if err := p.saveTile(tileDir, "default."+ext, levelImg.SubImage(rect)); err != nil {
+					return err
+				}
+			}
+		}
+
+		// whole page fits into one tile, viewers request it as a full region and need no smaller levels
+		if b.Dx() <= p.TileSize && b.Dy() <= p.TileSize {
+			info.Sizes = append(info.Sizes, iiifSize{Width: b.Dx(), Height: b.Dy()})
+			tileDir := filepath.Join(pageDir, "full", fmt.Sprintf("%d,", b.Dx()), "0")
+			if err := p.saveTile(tileDir, "default."+ext, levelImg); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	info.Tiles = []iiifTile{{Width: p.TileSize, ScaleFactors: scaleFactors}}
+
+	return writeJSON(filepath.Join(pageDir, "info.json"), info)
+}
+
+// xyzInfo describes XYZ pyramid for map viewers since the layout has no standard descriptor
+type xyzInfo struct {
+	Width    int `json:"width"`
+	Height   int `json:"height"`
+	TileSize int `json:"tileSize"`
+	MinZoom  int `json:"minZoom"`
+	MaxZoom  int `json:"maxZoom"`
+}
+
+// writeXYZ saves slippy map style name/<z>/<x>/<y> tiles, edge tiles are padded to full tile size
+func (p *Pyramid) writeXYZ(name string, levels []*image.RGBA) error {
+	full := levels[0].Bounds()
+	pageDir := filepath.Join(p.SavePath, name)
+	ext := p.ext()
+
+	// zoom 0 is the level where the whole page fits into a single tile
+	maxZoom := int(math.Ceil(math.Log2(float64(max(full.Dx(), full.Dy())) / float64(p.TileSize))))
+	maxZoom = max(min(maxZoom, len(levels)-1), 0)
+
+	for zoom := 0; zoom <= maxZoom; zoom++ {
+		levelImg := levels[maxZoom-zoom]
+		b := levelImg.Bounds()
+		for x := 0; x*p.TileSize < b.Dx(); x++ {
+			for y := 0; y*p.TileSize < b.Dy(); y++ {
+				rect := image.Rect(x*p.TileSize, y*p.TileSize, (x+1)*p.TileSize, (y+1)*p.TileSize)
+				tile := image.NewRGBA(image.Rect(0, 0, p.TileSize, p.TileSize))
+				if p.ImgType != "png" {
+					draw.Draw(tile, tile.Bounds(), image.White, image.Point{}, draw.Src)
+				}
+				draw.Draw(tile, tile.Bounds(), levelImg, rect.Min, draw.Over)
+
+				tileDir := filepath.Join(pageDir, fmt.Sprint(zoom), fmt.Sprint(x))
+				if err := p.saveTile(tileDir, fmt.Sprintf("%d.%s", y, ext), tile); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return writeJSON(filepath.Join(pageDir, "tiles.json"), xyzInfo{
+		Width:    full.Dx(),
+		Height:   full.Dy(),
+		TileSize: p.TileSize,
+		MaxZoom:  maxZoom,
+	})
+}
+
+// saveTile creates tile directory if needed and saves tile into it
+func (p *Pyramid) saveTile(dir, fname string, tile image.Image) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return imageutils.Save(filepath.Join(dir, fname), p.ImgType, tile)
+}
+
+// ext normalizes image type to the extension expected by tile viewers
+func (p *Pyramid) ext() string {
+	if p.ImgType == "jpeg" {
+		return "jpg"
+	}
+	return p.ImgType
+}
+
+// halvings returns image followed by its copies halved in size down to a single pixel
+func halvings(img *image.RGBA) []*image.RGBA {
+	levels := []*image.RGBA{img}
+	for b := img.Bounds(); b.Dx() > 1 || b.Dy() > 1; b = levels[len(levels)-1].Bounds() {
+		levels = append(levels, imageutils.Resize(levels[len(levels)-1], (b.Dx()+1)/2, (b.Dy()+1)/2))
+	}
+	return levels
+}
+
+// writeJSON saves value as indented JSON file
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
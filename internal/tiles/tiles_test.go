@@ -0,0 +1,192 @@
+package tiles
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type tileTestCase struct {
+	comment  string
+	format   string
+	overlap  int
+	tile     string
+	expected image.Point
+}
+
+// tiles of a 600x300 page cut into 256 pixel tiles, edge tiles are 88x44 at full resolution
+var TileTestCase = []tileTestCase{
+	{
+		comment:  "DZI inner tile with overlap",
+		format:   FormatDZI,
+		overlap:  1,
+		tile:     "page001_files/10/1_0.png",
+		expected: image.Pt(258, 257),
+	},
+	{
+		comment:  "DZI corner edge tile",
+		format:   FormatDZI,
+		overlap:  1,
+		tile:     "page001_files/10/2_1.png",
+		expected: image.Pt(89, 45),
+	},
+	{
+		comment:  "DZI edge tile of halved level",
+		format:   FormatDZI,
+		overlap:  1,
+		tile:     "page001_files/9/1_0.png",
+		expected: image.Pt(45, 150),
+	},
+	{
+		comment:  "DZI single pixel level",
+		format:   FormatDZI,
+		tile:     "page001_files/0/0_0.png",
+		expected: image.Pt(1, 1),
+	},
+	{
+		comment:  "IIIF corner edge tile",
+		format:   FormatIIIF,
+		tile:     "page001/512,256,88,44/88,/0/default.png",
+		expected: image.Pt(88, 44),
+	},
+	{
+		comment:  "IIIF edge tile of halved level in full resolution region",
+		format:   FormatIIIF,
+		tile:     "page001/512,0,88,300/44,/0/default.png",
+		expected: image.Pt(44, 150),
+	},
+	{
+		comment:  "IIIF full region of level fitting into a tile",
+		format:   FormatIIIF,
+		tile:     "page001/full/150,/0/default.png",
+		expected: image.Pt(150, 75),
+	},
+	{
+		comment:  "XYZ edge tile is padded",
+		format:   FormatXYZ,
+		tile:     "page001/2/2/1.png",
+		expected: image.Pt(256, 256),
+	},
+	{
+		comment:  "XYZ zoom 0 is a single tile",
+		format:   FormatXYZ,
+		tile:     "page001/0/0/0.png",
+		expected: image.Pt(256, 256),
+	},
+}
+
+func TestPyramidTiles(t *testing.T) {
+	page := image.NewRGBA(image.Rect(0, 0, 600, 300))
+	for i := range page.Pix {
+		page.Pix[i] = 0xff
+	}
+
+	for _, tc := range TileTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			dir := t.TempDir()
+			p := &Pyramid{Format: tc.format, TileSize: 256, Overlap: tc.overlap, ImgType: "png", SavePath: dir, Prefix: "page"}
+			if err := p.Collect(0, page); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := pngSize(filepath.Join(dir, tc.tile))
+			if err != nil {
+				t.Fatalf("%s test. tile %s: %v", tc.comment, tc.tile, err)
+			}
+			if got != tc.expected {
+				t.Errorf("%s test. want: %v, got: %v", tc.comment, tc.expected, got)
+			}
+		})
+	}
+}
+
+type tileMissingTestCase struct {
+	comment string
+	format  string
+	tile    string
+}
+
+// tiles past the edge of a 600x300 page are not written
+var TileMissingTestCase = []tileMissingTestCase{
+	{
+		comment: "DZI column past the edge",
+		format:  FormatDZI,
+		tile:    "page001_files/10/3_0.png",
+	},
+	{
+		comment: "IIIF level smaller than fitting one",
+		format:  FormatIIIF,
+		tile:    "page001/full/75,/0/default.png",
+	},
+	{
+		comment: "XYZ row past the edge",
+		format:  FormatXYZ,
+		tile:    "page001/2/0/2.png",
+	},
+	{
+		comment: "XYZ zoom past full resolution",
+		format:  FormatXYZ,
+		tile:    "page001/3/0/0.png",
+	},
+}
+
+func TestPyramidTilesMissing(t *testing.T) {
+	page := image.NewRGBA(image.Rect(0, 0, 600, 300))
+	for _, tc := range TileMissingTestCase {
+		t.Run(tc.comment, func(t *testing.T) {
+			dir := t.TempDir()
+			p := &Pyramid{Format: tc.format, TileSize: 256, ImgType: "png", SavePath: dir, Prefix: "page"}
+			if err := p.Collect(0, page); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tc.tile)); err == nil {
+				t.Errorf("%s test. tile %s should not exist", tc.comment, tc.tile)
+			}
+		})
+	}
+}
+
+func TestXYZPadding(t *testing.T) {
+	page := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	for i := range page.Pix {
+		page.Pix[i] = 0xff
+	}
+	dir := t.TempDir()
+	p := &Pyramid{Format: FormatXYZ, TileSize: 256, ImgType: "png", SavePath: dir, Prefix: "page"}
+	if err := p.Collect(0, page); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "page001/1/1/0.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tile, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// page covers 44x100 pixels of the edge tile, png tiles are padded with transparency
+	inside := color.NRGBAModel.Convert(tile.At(43, 99)).(color.NRGBA)
+	outside := color.NRGBAModel.Convert(tile.At(44, 100)).(color.NRGBA)
+	if inside.A != 0xff || outside.A != 0 {
+		t.Errorf("padding test. want: opaque page and transparent padding, got: %v and %v", inside, outside)
+	}
+}
+
+// pngSize reads dimensions of a saved tile
+func pngSize(path string) (image.Point, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Point{}, err
+	}
+	defer f.Close()
+	cfg, err := png.DecodeConfig(f)
+	if err != nil {
+		return image.Point{}, err
+	}
+	return image.Pt(cfg.Width, cfg.Height), nil
+}